@@ -0,0 +1,251 @@
+package goway
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// request data can't be bound to struct.
+var ErrBind = errors.New("goway: bind failed")
+
+// struct tags with request data sources (in priority order).
+var bindSources = []string{"path", "query", "header"}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// struct field binding error.
+type FieldError struct {
+	// struct field name.
+	Field string
+
+	// path, query or header.
+	Source string
+
+	// variable name, query key or header name.
+	Key string
+
+	// value that can't be converted.
+	Value string
+
+	// conversion error.
+	Cause error
+}
+
+func (f *FieldError) Error() string {
+	return fmt.Sprintf("%v %q (field %v): %q: %v", f.Source, f.Key, f.Field, f.Value, f.Cause)
+}
+
+func (f *FieldError) Unwrap() error {
+	return f.Cause
+}
+
+// all binding errors.
+type BindErrors []*FieldError
+
+func (b BindErrors) Error() string {
+	var messages = make([]string, 0, len(b))
+	for _, err := range b {
+		messages = append(messages, err.Error())
+	}
+	return ErrBind.Error() + ": " + strings.Join(messages, "; ")
+}
+
+// errors.Is(err, ErrBind) works.
+func (b BindErrors) Is(target error) bool {
+	return target == ErrBind
+}
+
+// fill dst struct fields from request by tags:
+//
+//	type GetUser struct {
+//		ID        int       `path:"id"`
+//		Page      int       `query:"page" default:"1"`
+//		Tags      []string  `query:"tag"`
+//		Since     time.Time `query:"since" layout:"2006-01-02"`
+//		RequestID *string   `header:"X-Request-ID"`
+//	}
+//
+// Supported types: string, bool, ints, uints, floats, time.Time
+// (layout tag, default: RFC3339), time.Duration, encoding.TextUnmarshaler,
+// slices and pointers of them. Slices filled by all query / header values,
+// or by comma separated path variable / default value.
+//
+// Default value used when request has no value. All errors
+// collected to BindErrors.
+func Bind(request *http.Request, dst interface{}) error {
+	var value = reflect.ValueOf(dst)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: destination should be pointer to struct", ErrBind)
+	}
+	value = value.Elem()
+
+	var errs BindErrors
+	var vars = Vars(request)
+	var query = request.URL.Query()
+	var structType = value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		var field = structType.Field(i)
+		if len(field.PkgPath) > 0 {
+			// unexported.
+			continue
+		}
+		var source, key, values = getBindValues(request, vars, query, field)
+		if len(source) < 1 {
+			// not tagged.
+			continue
+		}
+		// path variable and default value can be comma separated list.
+		var isCommaList = source == "path"
+		if len(values) < 1 {
+			var defaultValue, hasDefault = field.Tag.Lookup("default")
+			if !hasDefault {
+				continue
+			}
+			values = []string{defaultValue}
+			isCommaList = true
+		}
+		if isCommaList && isSliceField(value.Field(i)) {
+			values = strings.Split(values[0], ",")
+		}
+		if err := setField(value.Field(i), values, field.Tag.Get("layout")); err != nil {
+			errs = append(errs, &FieldError{
+				Field:  field.Name,
+				Source: source,
+				Key:    key,
+				Value:  strings.Join(values, ","),
+				Cause:  err,
+			})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// get field values from first tagged source with values.
+//
+// Empty source - field not tagged. Empty values - request has no values.
+func getBindValues(request *http.Request, vars map[string]string, query url.Values, field reflect.StructField) (source string, key string, values []string) {
+	for _, tag := range bindSources {
+		var tagKey, ok = field.Tag.Lookup(tag)
+		if !ok || len(tagKey) < 1 {
+			continue
+		}
+		if len(source) < 1 {
+			source, key = tag, tagKey
+		}
+		switch tag {
+		case "path":
+			if pathValue, exists := vars[tagKey]; exists {
+				values = []string{pathValue}
+			}
+		case "query":
+			values = query[tagKey]
+		case "header":
+			values = request.Header.Values(tagKey)
+		}
+		if len(values) > 0 {
+			return tag, tagKey, values
+		}
+	}
+	return
+}
+
+// is field filled by many values?
+func isSliceField(field reflect.Value) bool {
+	return field.Kind() == reflect.Slice && !reflect.PtrTo(field.Type()).Implements(textUnmarshalerType)
+}
+
+// set field by values. Not slices get first value.
+func setField(field reflect.Value, values []string, layout string) error {
+	if isSliceField(field) {
+		var slice = reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValue(slice.Index(i), value, layout); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	return setValue(field, values[0], layout)
+}
+
+// convert value to field type and set it.
+func setValue(field reflect.Value, value string, layout string) error {
+	if field.Kind() == reflect.Ptr {
+		var created = reflect.New(field.Type().Elem())
+		if err := setValue(created.Elem(), value, layout); err != nil {
+			return err
+		}
+		field.Set(created)
+		return nil
+	}
+	switch field.Type() {
+	case timeType:
+		if len(layout) < 1 {
+			layout = time.RFC3339
+		}
+		var converted, err = time.Parse(layout, value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(converted))
+		return nil
+	case durationType:
+		var converted, err = time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(converted))
+		return nil
+	}
+
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		var converted, err = strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(converted)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var converted, err = strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(converted)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var converted, err = strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(converted)
+	case reflect.Float32, reflect.Float64:
+		var converted, err = strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(converted)
+	default:
+		return fmt.Errorf("unsupported type %v", field.Type())
+	}
+	return nil
+}
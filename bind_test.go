@@ -0,0 +1,102 @@
+package goway
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBind(t *testing.T) {
+	type target struct {
+		ID        int64         `path:"id"`
+		Tags      []string      `path:"tags"`
+		Name      string        `path:"name"`
+		Page      int           `query:"page" default:"1"`
+		Limit     uint8         `query:"limit" default:"20"`
+		Sort      []string      `query:"sort" default:"name,id"`
+		Filters   []string      `query:"filter"`
+		Active    *bool         `query:"active"`
+		Missing   *int          `query:"missing"`
+		Since     time.Time     `query:"since" layout:"2006-01-02"`
+		Timeout   time.Duration `query:"timeout"`
+		Ratio     float64       `query:"ratio"`
+		IP        net.IP        `query:"ip"`
+		RequestID string        `header:"X-Request-ID"`
+		Lang      string        `path:"lang" query:"lang" header:"Accept-Language"`
+		Skipped   string
+		private   string `query:"page"`
+	}
+
+	var request = httptest.NewRequest(http.MethodGet,
+		"/?limit=50&filter=a&filter=b&active=true&since=2022-05-01&timeout=1m&ratio=0.5&ip=127.0.0.1", nil)
+	request.Header.Set("X-Request-ID", "abc")
+	request.Header.Set("Accept-Language", "en")
+	addVarToContext(request, "id", "12")
+	addVarToContext(request, "tags", "go,http")
+	addVarToContext(request, "name", "a,b")
+
+	var got target
+	if err := Bind(request, &got); err != nil {
+		t.Fatal(err)
+	}
+	var active = true
+	var expected = target{
+		ID:        12,
+		Tags:      []string{"go", "http"},
+		Name:      "a,b",
+		Page:      1,
+		Limit:     50,
+		Sort:      []string{"name", "id"},
+		Filters:   []string{"a", "b"},
+		Active:    &active,
+		Since:     time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC),
+		Timeout:   time.Minute,
+		Ratio:     0.5,
+		IP:        net.ParseIP("127.0.0.1"),
+		RequestID: "abc",
+		Lang:      "en",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected: %+v | got: %+v", expected, got)
+	}
+}
+
+func TestBind_Errors(t *testing.T) {
+	type target struct {
+		ID    int               `path:"id"`
+		Page  int               `query:"page"`
+		Since time.Time         `query:"since"`
+		Map   map[string]string `query:"map"`
+	}
+	var request = httptest.NewRequest(http.MethodGet, "/?page=x&since=today&map=a", nil)
+	addVarToContext(request, "id", "abc")
+
+	var err = Bind(request, &target{})
+	if !errors.Is(err, ErrBind) {
+		t.Fatalf("expected: %v | got: %v", ErrBind, err)
+	}
+	var bindErrs BindErrors
+	if !errors.As(err, &bindErrs) {
+		t.Fatalf("expected BindErrors")
+	}
+	var fields = []string{"ID", "Page", "Since", "Map"}
+	if len(bindErrs) != len(fields) {
+		t.Fatalf("expected errors: %v | got: %v", len(fields), bindErrs)
+	}
+	for i, fieldErr := range bindErrs {
+		if fieldErr.Field != fields[i] {
+			t.Fatalf("expected field: %v | got: %v", fields[i], fieldErr.Field)
+		}
+	}
+
+	// wrong destination.
+	for _, dst := range []interface{}{nil, target{}, new(int)} {
+		if err := Bind(request, dst); !errors.Is(err, ErrBind) {
+			t.Fatalf("expected: %v | got: %v", ErrBind, err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package goway
+
+import (
+	"regexp"
+	"sync"
+)
+
+// built-in route variable types.
+//
+// example: {id:int}, {token:uuid}.
+var varTypes = map[string]string{
+	"int":   `-?[0-9]+`,
+	"uuid":  `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+	"slug":  `[a-z0-9]+(?:-[a-z0-9]+)*`,
+	"alpha": `[a-zA-Z]+`,
+}
+
+// compiled constraints by constraint string.
+var constraintsCache sync.Map
+
+// compile route variable constraint.
+//
+// Constraint is built-in type name or regular expression (like [0-9]+).
+// Expression must match whole path piece.
+//
+// Returns nil if constraint empty. Panics if expression invalid.
+func compileConstraint(constraint string) *regexp.Regexp {
+	if len(constraint) < 1 {
+		return nil
+	}
+	if cached, ok := constraintsCache.Load(constraint); ok {
+		return cached.(*regexp.Regexp)
+	}
+	var expr, isType = varTypes[constraint]
+	if !isType {
+		expr = constraint
+	}
+	var compiled, err = regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		panic("goway: invalid route variable constraint: " + constraint + ": " + err.Error())
+	}
+	constraintsCache.Store(constraint, compiled)
+	return compiled
+}
@@ -0,0 +1,210 @@
+package goway
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// methods in Allow header for routes without allowed methods.
+var anyMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// CORS policy.
+type CORS struct {
+	// allowed origins like: https://example.com.
+	//
+	// "*" - any origin.
+	AllowedOrigins []string
+
+	// allowed methods.
+	//
+	// If empty - methods of routes with requested path.
+	AllowedMethods []string
+
+	// allowed request headers.
+	//
+	// "*" - any requested headers.
+	AllowedHeaders []string
+
+	// response headers available for browser.
+	ExposedHeaders []string
+
+	// allow cookies / authorization headers.
+	AllowCredentials bool
+
+	// how long preflight response can be cached. 0 - not set.
+	MaxAge time.Duration
+}
+
+// get allowed origin for Access-Control-Allow-Origin header.
+//
+// Returns empty string if origin not allowed.
+func (c *CORS) getAllowedOrigin(origin string) string {
+	if len(origin) < 1 {
+		return ""
+	}
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" {
+			// with credentials "*" not works.
+			if c.AllowCredentials {
+				return origin
+			}
+			return "*"
+		}
+		if strings.EqualFold(allowed, origin) {
+			return origin
+		}
+	}
+	return ""
+}
+
+// set headers for simple (not preflight) request.
+func (c *CORS) setSimpleHeaders(response http.ResponseWriter, request *http.Request) {
+	var header = response.Header()
+	header.Add("Vary", "Origin")
+	var origin = c.getAllowedOrigin(request.Header.Get("Origin"))
+	if len(origin) < 1 {
+		return
+	}
+	header.Set("Access-Control-Allow-Origin", origin)
+	if c.AllowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if len(c.ExposedHeaders) > 0 {
+		header.Set("Access-Control-Expose-Headers", strings.Join(c.ExposedHeaders, ", "))
+	}
+}
+
+// set headers for preflight request.
+//
+// allowed - methods of routes with requested path.
+func (c *CORS) setPreflightHeaders(response http.ResponseWriter, request *http.Request, allowed []string) {
+	var header = response.Header()
+	header.Add("Vary", "Origin")
+	header.Add("Vary", "Access-Control-Request-Method")
+	header.Add("Vary", "Access-Control-Request-Headers")
+	var origin = c.getAllowedOrigin(request.Header.Get("Origin"))
+	if len(origin) < 1 {
+		return
+	}
+	if len(c.AllowedMethods) > 0 {
+		allowed = processAllowedMethods(nil, c.AllowedMethods...)
+	}
+	var requestedMethod = request.Header.Get("Access-Control-Request-Method")
+	if !isMethodAllowed(allowed, requestedMethod) {
+		return
+	}
+
+	header.Set("Access-Control-Allow-Origin", origin)
+	header.Set("Access-Control-Allow-Methods", strings.Join(allowed, ", "))
+	var requestedHeaders = request.Header.Get("Access-Control-Request-Headers")
+	if len(requestedHeaders) > 0 {
+		var allowedHeaders = strings.Join(c.AllowedHeaders, ", ")
+		for _, allowedHeader := range c.AllowedHeaders {
+			if allowedHeader == "*" {
+				allowedHeaders = requestedHeaders
+				break
+			}
+		}
+		if len(allowedHeaders) > 0 {
+			header.Set("Access-Control-Allow-Headers", allowedHeaders)
+		}
+	}
+	if c.AllowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if c.MaxAge > 0 {
+		header.Set("Access-Control-Max-Age", strconv.Itoa(int(c.MaxAge.Seconds())))
+	}
+}
+
+// is request CORS preflight?
+func isPreflight(request *http.Request) bool {
+	return request.Method == http.MethodOptions &&
+		len(request.Header.Get("Origin")) > 0 &&
+		len(request.Header.Get("Access-Control-Request-Method")) > 0
+}
+
+// answer OPTIONS request with routes of requested path.
+//
+// Returns false if OPTIONS should be served by route
+// (auto OPTIONS disabled or route accepts OPTIONS by itself).
+func serveOptions(response http.ResponseWriter, request *http.Request, candidates []*Route) (isServed bool) {
+	if len(candidates) < 1 {
+		return
+	}
+	var allowed = make([]string, 0)
+	for _, candidate := range candidates {
+		// methods of route groups also matter.
+		var methods = candidate.effectiveMethods()
+		if methods == nil {
+			allowed = append(allowed, anyMethods...)
+			continue
+		}
+		for _, method := range methods {
+			if method == http.MethodOptions {
+				// route handles OPTIONS.
+				return
+			}
+		}
+		allowed = append(allowed, methods...)
+	}
+
+	var first = candidates[0]
+	var policy = first.getCORS()
+	if !first.isAutoOptions() && policy == nil {
+		return
+	}
+
+	allowed = processAllowedMethods(addImplicitHead(allowed), http.MethodOptions)
+	response.Header().Set("Allow", strings.Join(allowed, ", "))
+	if policy != nil && isPreflight(request) {
+		policy.setPreflightHeaders(response, request, allowed)
+	}
+	response.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+// is OPTIONS requests answered automatically?
+func (r *Router) isAutoOptions() bool {
+	for current := r; current != nil; current = current.parent {
+		if current.autoOptions != nil {
+			return *current.autoOptions
+		}
+	}
+	return false
+}
+
+// get CORS policy of router or its parents.
+func (r *Router) getCORS() *CORS {
+	for current := r; current != nil; current = current.parent {
+		if current.cors != nil {
+			return current.cors
+		}
+	}
+	return nil
+}
+
+// is OPTIONS requests answered automatically?
+func (r *Route) isAutoOptions() bool {
+	if r.autoOptions != nil {
+		return *r.autoOptions
+	}
+	return r.router.isAutoOptions()
+}
+
+// get CORS policy of route or its router.
+func (r *Route) getCORS() *CORS {
+	if r.cors != nil {
+		return r.cors
+	}
+	return r.router.getCORS()
+}
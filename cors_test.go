@@ -0,0 +1,117 @@
+package goway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRouting_AutoOptions(t *testing.T) {
+	var root = New().AutoOptions(true)
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("handler"))
+	}
+	root.Route("/users", handler).Methods(http.MethodGet, http.MethodPost)
+	root.Route("/manual", handler).Methods(http.MethodOptions)
+	root.Route("/disabled", handler).Methods(http.MethodGet).AutoOptions(false)
+	root.Group("/api").Methods(http.MethodGet).Route("/any", handler)
+
+	type caser struct {
+		num      int
+		path     string
+		status   int
+		allow    string
+		expected string
+	}
+	var cases = []caser{
+		{num: 1, path: "/users", status: http.StatusNoContent, allow: "GET, POST, HEAD, OPTIONS"},
+		{num: 2, path: "/manual", status: http.StatusOK, expected: "handler"},
+		{num: 3, path: "/disabled", status: http.StatusMethodNotAllowed, allow: "GET, HEAD"},
+		{num: 4, path: "/api/any", status: http.StatusNoContent, allow: "GET, HEAD, OPTIONS"},
+	}
+	for _, cased := range cases {
+		var req = httptest.NewRequest(http.MethodOptions, cased.path, nil)
+		var rec = httptest.NewRecorder()
+		root.ServeHTTP(rec, req)
+		if rec.Code != cased.status {
+			t.Fatalf("case num: %v | expected status: %v | got: %v", cased.num, cased.status, rec.Code)
+		}
+		if allow := rec.Header().Get("Allow"); allow != cased.allow {
+			t.Fatalf("case num: %v | expected Allow: %v | got: %v", cased.num, cased.allow, allow)
+		}
+		if len(cased.expected) > 0 && rec.Body.String() != cased.expected {
+			t.Fatalf("case num: %v | expected body: %v | got: %v", cased.num, cased.expected, rec.Body.String())
+		}
+	}
+}
+
+func TestRouting_CORS(t *testing.T) {
+	var root = New().CORS(&CORS{
+		AllowedOrigins:   []string{"https://example.com"},
+		AllowedHeaders:   []string{"Content-Type"},
+		ExposedHeaders:   []string{"X-Total"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})
+	var handler = func(w http.ResponseWriter, r *http.Request) {}
+	root.Route("/users", handler).Methods(http.MethodGet, http.MethodDelete)
+	root.Route("/public", handler).Methods(http.MethodGet).CORS(&CORS{
+		AllowedOrigins: []string{"*"},
+		AllowedHeaders: []string{"*"},
+	})
+
+	// preflight.
+	var req = httptest.NewRequest(http.MethodOptions, "/users", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodDelete)
+	req.Header.Set("Access-Control-Request-Headers", "Content-Type")
+	var rec = httptest.NewRecorder()
+	root.ServeHTTP(rec, req)
+	var expectedHeaders = map[string]string{
+		"Access-Control-Allow-Origin":      "https://example.com",
+		"Access-Control-Allow-Methods":     "GET, DELETE, HEAD, OPTIONS",
+		"Access-Control-Allow-Headers":     "Content-Type",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Max-Age":           "600",
+	}
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status: %v | got: %v", http.StatusNoContent, rec.Code)
+	}
+	for name, expected := range expectedHeaders {
+		if got := rec.Header().Get(name); got != expected {
+			t.Fatalf("header: %v | expected: %v | got: %v", name, expected, got)
+		}
+	}
+
+	// preflight from unknown origin.
+	req.Header.Set("Origin", "https://evil.com")
+	rec = httptest.NewRecorder()
+	root.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); len(got) > 0 {
+		t.Fatalf("origin should not be allowed, got: %v", got)
+	}
+
+	// simple request.
+	req = httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("Origin", "https://example.com")
+	rec = httptest.NewRecorder()
+	root.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Expose-Headers"); got != "X-Total" {
+		t.Fatalf("expected exposed headers: X-Total | got: %v", got)
+	}
+
+	// route policy.
+	req = httptest.NewRequest(http.MethodOptions, "/public", nil)
+	req.Header.Set("Origin", "https://other.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	req.Header.Set("Access-Control-Request-Headers", "X-Custom")
+	rec = httptest.NewRecorder()
+	root.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Fatalf("expected origin: * | got: %v", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); got != "X-Custom" {
+		t.Fatalf("expected headers: X-Custom | got: %v", got)
+	}
+}
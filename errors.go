@@ -0,0 +1,166 @@
+package goway
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// route endpoint that returns error.
+//
+// Error sent by error handler of route (see Router.ErrorHandler).
+// Return error before writing response.
+type ErrorRouteHandler func(http.ResponseWriter, *http.Request) error
+
+// sends error response.
+type ErrorHandler func(http.ResponseWriter, *http.Request, error)
+
+// when route endpoint returns error.
+//
+// Used by routers without own handler (see Router.ErrorHandler).
+//
+// *HTTPError sent with its status and message,
+// route variable and bind errors (see VarInt, Bind) as 400,
+// other errors as 500 (without error text).
+// Response is JSON if client accepts it, otherwise text.
+var HandlerError = getDefaultErrorHandler()
+
+// error with HTTP status.
+type HTTPError struct {
+	// response status code like: 404.
+	Status int
+
+	// message for client. Default: status text.
+	Message string
+
+	// original error (not sent to client).
+	Cause error
+}
+
+func (e *HTTPError) Error() string {
+	var message = e.getMessage()
+	if e.Cause != nil {
+		return fmt.Sprintf("%v %v: %v", e.Status, message, e.Cause)
+	}
+	return fmt.Sprintf("%v %v", e.Status, message)
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Cause
+}
+
+// get message or status text.
+func (e *HTTPError) getMessage() string {
+	if len(e.Message) > 0 {
+		return e.Message
+	}
+	return http.StatusText(e.Status)
+}
+
+// add route with endpoint that returns error.
+func (r *Router) RouteE(to string, handler ErrorRouteHandler) *Route {
+	var route = r.Handle(to, nil)
+	route.handler = &errorRoute{route: route, handler: handler}
+	return route
+}
+
+// set error handler. Groups and routes use handler of parent, if not set.
+func (r *Router) ErrorHandler(handler ErrorHandler) *Router {
+	r.checkFrozen()
+	r.errorHandler = handler
+	return r
+}
+
+// set error handler (overrides router handler).
+func (r *Route) ErrorHandler(handler ErrorHandler) *Route {
+	r.checkFrozen()
+	r.errorHandler = handler
+	return r
+}
+
+// get error handler of router or its parents (or HandlerError).
+func (r *Router) getErrorHandler() ErrorHandler {
+	for current := r; current != nil; current = current.parent {
+		if current.errorHandler != nil {
+			return current.errorHandler
+		}
+	}
+	return HandlerError
+}
+
+// get error handler of route or its routers.
+func (r *Route) getErrorHandler() ErrorHandler {
+	if r.errorHandler != nil {
+		return r.errorHandler
+	}
+	if r.router != nil {
+		return r.router.getErrorHandler()
+	}
+	return HandlerError
+}
+
+// calls route endpoint and sends returned error.
+type errorRoute struct {
+	route *Route
+
+	handler ErrorRouteHandler
+}
+
+func (e *errorRoute) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	if err := e.handler(response, request); err != nil {
+		e.route.getErrorHandler()(response, request, err)
+	}
+}
+
+// default error handler.
+func getDefaultErrorHandler() ErrorHandler {
+	return func(w http.ResponseWriter, r *http.Request, err error) {
+		var httpErr *HTTPError
+		switch {
+		case errors.As(err, &httpErr):
+		case isBadRequest(err):
+			httpErr = &HTTPError{Status: http.StatusBadRequest, Message: err.Error()}
+		default:
+			httpErr = &HTTPError{Status: http.StatusInternalServerError}
+		}
+		writeError(w, r, httpErr.Status, httpErr.getMessage())
+	}
+}
+
+// is error caused by request (like invalid route variable)?
+func isBadRequest(err error) bool {
+	return errors.Is(err, ErrVarInvalid) || errors.Is(err, ErrVarNotFound) || errors.Is(err, ErrBind)
+}
+
+// send error as JSON (if client accepts it) or text.
+func writeError(response http.ResponseWriter, request *http.Request, status int, message string) {
+	var header = response.Header()
+	header.Set("X-Content-Type-Options", "nosniff")
+	if !isJSONAccepted(request) {
+		header.Set("Content-Type", "text/plain; charset=utf-8")
+		response.WriteHeader(status)
+		fmt.Fprintln(response, message)
+		return
+	}
+	header.Set("Content-Type", "application/json")
+	response.WriteHeader(status)
+	json.NewEncoder(response).Encode(struct {
+		Status  int    `json:"status"`
+		Message string `json:"message"`
+	}{Status: status, Message: message})
+}
+
+// is JSON in Accept header (like application/json or application/problem+json)?
+func isJSONAccepted(request *http.Request) bool {
+	for _, header := range request.Header.Values("Accept") {
+		for _, part := range strings.Split(header, ",") {
+			var mediaType = strings.TrimSpace(strings.Split(part, ";")[0])
+			if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+				return true
+			}
+		}
+	}
+	return false
+}
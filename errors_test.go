@@ -0,0 +1,104 @@
+package goway
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter_RouteE(t *testing.T) {
+	var errNoUser = errors.New("no user")
+	var handler = func(err error) ErrorRouteHandler {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(w, "ok")
+			return nil
+		}
+	}
+	var custom = func(name string) ErrorHandler {
+		return func(w http.ResponseWriter, r *http.Request, err error) {
+			w.WriteHeader(http.StatusTeapot)
+			fmt.Fprint(w, name, ": ", err)
+		}
+	}
+
+	var root = New()
+	root.RouteE("/ok", handler(nil))
+	root.RouteE("/missing", handler(&HTTPError{Status: http.StatusNotFound, Message: "user not found", Cause: errNoUser}))
+	root.RouteE("/status", handler(&HTTPError{Status: http.StatusConflict}))
+	root.RouteE("/wrapped", handler(fmt.Errorf("load: %w", &HTTPError{Status: http.StatusForbidden})))
+	root.RouteE("/internal", handler(errNoUser))
+	root.RouteE("/var/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		var _, err = VarInt(r, "id")
+		return err
+	})
+	root.RouteE("/bind", func(w http.ResponseWriter, r *http.Request) error {
+		var dst struct {
+			Page int `query:"page"`
+		}
+		return Bind(r, &dst)
+	})
+	var api = root.Group("/api").ErrorHandler(custom("api"))
+	api.RouteE("/fail", handler(errNoUser))
+	api.Group("/v1").RouteE("/fail", handler(errNoUser))
+	api.RouteE("/route", handler(errNoUser)).ErrorHandler(custom("route"))
+
+	type caser struct {
+		num         int
+		path        string
+		accept      string
+		status      int
+		contentType string
+		expected    string
+	}
+	var cases = []caser{
+		{num: 1, path: "/ok", status: 200, expected: "ok"},
+		{num: 2, path: "/missing", status: 404, contentType: "text/plain; charset=utf-8", expected: "user not found\n"},
+		{num: 3, path: "/missing", accept: "text/html, application/json;q=0.9", status: 404,
+			contentType: "application/json", expected: `{"status":404,"message":"user not found"}` + "\n"},
+		{num: 4, path: "/missing", accept: "application/problem+json", status: 404,
+			contentType: "application/json", expected: `{"status":404,"message":"user not found"}` + "\n"},
+		{num: 5, path: "/status", status: 409, contentType: "text/plain; charset=utf-8", expected: "Conflict\n"},
+		{num: 6, path: "/wrapped", status: 403, contentType: "text/plain; charset=utf-8", expected: "Forbidden\n"},
+		// error text not sent to client.
+		{num: 7, path: "/internal", status: 500, contentType: "text/plain; charset=utf-8", expected: "Internal Server Error\n"},
+		{num: 8, path: "/api/fail", status: 418, expected: "api: no user"},
+		// handler of parent group.
+		{num: 9, path: "/api/v1/fail", status: 418, expected: "api: no user"},
+		{num: 10, path: "/api/route", status: 418, expected: "route: no user"},
+		// client errors.
+		{num: 11, path: "/var/abc", status: 400, contentType: "text/plain; charset=utf-8",
+			expected: "goway: invalid route variable: id (int): \"abc\"\n"},
+		{num: 12, path: "/bind?page=x", accept: "application/json", status: 400, contentType: "application/json",
+			expected: `{"status":400,"message":"goway: bind failed: query \"page\" (field Page): \"x\": strconv.ParseInt: parsing \"x\": invalid syntax"}` + "\n"},
+	}
+	for _, c := range cases {
+		var recorder = httptest.NewRecorder()
+		var request = httptest.NewRequest(http.MethodGet, c.path, nil)
+		if len(c.accept) > 0 {
+			request.Header.Set("Accept", c.accept)
+		}
+		root.ServeHTTP(recorder, request)
+		if recorder.Code != c.status || recorder.Body.String() != c.expected {
+			t.Fatalf("case num: %v | expected: %v %q | got: %v %q", c.num, c.status, c.expected, recorder.Code, recorder.Body.String())
+		}
+		if contentType := recorder.Header().Get("Content-Type"); len(c.contentType) > 0 && contentType != c.contentType {
+			t.Fatalf("case num: %v | expected content type: %v | got: %v", c.num, c.contentType, contentType)
+		}
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	var cause = errors.New("db down")
+	var err error = &HTTPError{Status: http.StatusServiceUnavailable, Cause: cause}
+	if err.Error() != "503 Service Unavailable: db down" {
+		t.Fatalf("unexpected error text: %v", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Fatalf("expected cause in chain")
+	}
+}
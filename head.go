@@ -0,0 +1,76 @@
+package goway
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// response writer for HEAD requests served by GET handlers.
+//
+// Discards body, but counts it for Content-Length header.
+// Status code and headers sended in finish().
+type headResponseWriter struct {
+	http.ResponseWriter
+
+	// status code from handler.
+	status int
+
+	// is WriteHeader() called?
+	isHeaderWritten bool
+
+	// body length.
+	length int
+
+	// is headers sent to original writer (by Flush)?
+	isSent bool
+}
+
+func (h *headResponseWriter) WriteHeader(status int) {
+	if h.isHeaderWritten {
+		return
+	}
+	h.isHeaderWritten = true
+	h.status = status
+}
+
+func (h *headResponseWriter) Write(data []byte) (int, error) {
+	h.WriteHeader(http.StatusOK)
+	h.length += len(data)
+	return len(data), nil
+}
+
+// send headers (without Content-Length, body not finished)
+// and flush (if supported by original writer).
+func (h *headResponseWriter) Flush() {
+	var flusher, ok = h.ResponseWriter.(http.Flusher)
+	if !ok {
+		return
+	}
+	h.WriteHeader(http.StatusOK)
+	if !h.isSent {
+		h.isSent = true
+		h.ResponseWriter.WriteHeader(h.status)
+	}
+	flusher.Flush()
+}
+
+// get original writer (for http.ResponseController).
+func (h *headResponseWriter) Unwrap() http.ResponseWriter {
+	return h.ResponseWriter
+}
+
+// send headers with Content-Length.
+func (h *headResponseWriter) finish() {
+	if h.isSent {
+		return
+	}
+	if !h.isHeaderWritten {
+		h.status = http.StatusOK
+	}
+	var header = h.ResponseWriter.Header()
+	var isLengthKnown = len(header.Get("Content-Length")) > 0 || len(header.Get("Transfer-Encoding")) > 0
+	if !isLengthKnown && h.length > 0 {
+		header.Set("Content-Length", strconv.Itoa(h.length))
+	}
+	h.ResponseWriter.WriteHeader(h.status)
+}
@@ -0,0 +1,145 @@
+package goway
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// request matcher (besides path and method).
+//
+// Failed matcher means group / route not matched,
+// and next group / route will be checked.
+type requestMatcher interface {
+	// returns variables from request (if exists).
+	match(request *http.Request) (vars []routeVar, isMatched bool)
+}
+
+// host pattern like: api.example.com or {tenant}.example.com.
+//
+// Variable constraints can't contain dots.
+type hostPattern struct {
+	// pattern labels like: [{tenant}, example, com].
+	labels []string
+
+	// variable names by label index.
+	varNames map[int]string
+
+	// variable constraints by label index.
+	constraints map[int]*regexp.Regexp
+}
+
+// create host pattern. Port in pattern ignored.
+func newHostPattern(pattern string) *hostPattern {
+	var host = &hostPattern{
+		varNames:    make(map[int]string),
+		constraints: make(map[int]*regexp.Regexp),
+	}
+	host.labels = strings.Split(stripPort(pattern), ".")
+	for i, label := range host.labels {
+		var isVar, name, constraint = parseRouteVar(label)
+		if !isVar {
+			// hosts are case-insensitive, but variable names and constraints not.
+			host.labels[i] = strings.ToLower(label)
+			continue
+		}
+		host.varNames[i] = name
+		host.constraints[i] = compileConstraint(constraint)
+	}
+	return host
+}
+
+func (h *hostPattern) match(request *http.Request) (vars []routeVar, isMatched bool) {
+	var host = request.Host
+	if len(host) < 1 {
+		host = request.URL.Host
+	}
+	var labels = strings.Split(stripPort(strings.ToLower(host)), ".")
+	if len(labels) != len(h.labels) {
+		return nil, false
+	}
+	for i, label := range labels {
+		var name, isVar = h.varNames[i]
+		if !isVar {
+			if label != h.labels[i] {
+				return nil, false
+			}
+			continue
+		}
+		var constraint = h.constraints[i]
+		if len(label) < 1 || (constraint != nil && !constraint.MatchString(label)) {
+			return nil, false
+		}
+		vars = append(vars, routeVar{name: name, value: label})
+	}
+	return vars, true
+}
+
+// build host by variables.
+func (h *hostPattern) build(vars map[string]string) (string, error) {
+	var labels = make([]string, len(h.labels))
+	for i, label := range h.labels {
+		var name, isVar = h.varNames[i]
+		if !isVar {
+			labels[i] = label
+			continue
+		}
+		var value, ok = vars[name]
+		if !ok || len(value) < 1 {
+			return "", fmt.Errorf("%w: missing %v", ErrWrongVars, name)
+		}
+		var constraint = h.constraints[i]
+		if constraint != nil && !constraint.MatchString(value) {
+			return "", fmt.Errorf("%w: %v not matches constraint", ErrWrongVars, name)
+		}
+		labels[i] = value
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// remove port from host like: example.com:8080 or [::1]:8080.
+func stripPort(host string) string {
+	// IPv6.
+	if strings.HasPrefix(host, "[") {
+		var end = strings.Index(host, "]")
+		if end > -1 {
+			return host[1:end]
+		}
+		return host
+	}
+	// colon can be in variable constraint.
+	var colon = strings.LastIndex(host, ":")
+	if colon > -1 && colon > strings.LastIndex(host, "}") {
+		return host[:colon]
+	}
+	return host
+}
+
+// get host pattern from matchers (if exists).
+func getHostPattern(matchers []requestMatcher) *hostPattern {
+	for _, matcher := range matchers {
+		if host, ok := matcher.(*hostPattern); ok {
+			return host
+		}
+	}
+	return nil
+}
+
+// match group only on requests with this host. Port ignored.
+//
+// Host can contain variables like: {tenant}.example.com.
+func (r *Router) Host(pattern string) *Router {
+	r.checkFrozen()
+	r.matchers = append(r.matchers, newHostPattern(pattern))
+	return r
+}
+
+// match route only on requests with this host. Port ignored.
+//
+// Host can contain variables like: {tenant}.example.com.
+func (r *Route) Host(pattern string) *Route {
+	r.checkFrozen()
+	r.matchers = append(r.matchers, newHostPattern(pattern))
+	return r
+}
@@ -0,0 +1,108 @@
+package goway
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStripPort(t *testing.T) {
+	var cases = map[string]string{
+		"example.com":      "example.com",
+		"example.com:8080": "example.com",
+		"[::1]:8080":       "::1",
+		"[::1]":            "::1",
+	}
+	for value, expected := range cases {
+		if result := stripPort(value); result != expected {
+			t.Fatalf("value: %v | expected: %v | got: %v", value, expected, result)
+		}
+	}
+}
+
+func TestRouting_Host(t *testing.T) {
+	var root = New()
+	var api = root.Group("").Host("api.example.com")
+	api.Route("/users", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "api users")
+	})
+	var tenant = root.Group("").Host("{tenant:alpha}.example.com:443")
+	tenant.Route("/users", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "tenant users: "+Vars(r)["tenant"])
+	}).Name("tenant-users")
+	root.Route("/users", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "any users")
+	})
+	root.Route("/about", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "about")
+	}).Host("example.com")
+
+	type caser struct {
+		num      int
+		host     string
+		path     string
+		expected string
+	}
+	var cases = []caser{
+		{num: 1, host: "api.example.com", path: "/users", expected: "api users"},
+		{num: 2, host: "API.example.com:8080", path: "/users", expected: "api users"},
+		{num: 3, host: "oklookat.example.com", path: "/users", expected: "tenant users: oklookat"},
+		{num: 4, host: "123.example.com", path: "/users", expected: "any users"},
+		{num: 5, host: "other.com", path: "/users", expected: "any users"},
+		{num: 6, host: "example.com", path: "/about", expected: "about"},
+		{num: 7, host: "other.com", path: "/about", expected: "not found"},
+	}
+	var oldHandler = Handler404
+	Handler404 = getDefaultHandler404()
+	defer func() {
+		Handler404 = oldHandler
+	}()
+	for _, cased := range cases {
+		var req = httptest.NewRequest(http.MethodGet, cased.path, nil)
+		req.Host = cased.host
+		var rec = httptest.NewRecorder()
+		root.ServeHTTP(rec, req)
+		if rec.Body.String() != cased.expected {
+			t.Fatalf("case num: %v | expected body: %v | got: %v", cased.num, cased.expected, rec.Body.String())
+		}
+	}
+
+	// URL with host.
+	var built, err = root.URL("tenant-users", "tenant", "oklookat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if built.Host != "oklookat.example.com" || built.Path != "/users" {
+		t.Fatalf("wrong URL: %v", built.String())
+	}
+	if _, err = root.URL("tenant-users", "tenant", "123"); err == nil {
+		t.Fatal("expected error for wrong host variable")
+	}
+}
+
+func TestHostPattern_Case(t *testing.T) {
+	var host = newHostPattern("{tenantID}.{code:[A-Z]+}.Example.COM")
+	if host.labels[2] != "example" || host.labels[3] != "com" {
+		t.Fatalf("expected lowercase static labels | got: %v", host.labels)
+	}
+	if host.varNames[0] != "tenantID" {
+		t.Fatalf("expected variable name as written | got: %v", host.varNames[0])
+	}
+	if constraint := host.constraints[1].String(); !strings.Contains(constraint, "[A-Z]+") {
+		t.Fatalf("expected constraint as written | got: %v", constraint)
+	}
+
+	var root = New()
+	root.Group("").Host("{tenantID}.Example.com").Route("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, Vars(r)["tenantID"])
+	})
+	var req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Host = "acme.example.com"
+	var rec = httptest.NewRecorder()
+	root.ServeHTTP(rec, req)
+	if rec.Body.String() != "acme" {
+		t.Fatalf("expected: acme | got: %v", rec.Body.String())
+	}
+}
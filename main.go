@@ -2,6 +2,7 @@ package goway
 
 import (
 	"net/http"
+	"sync/atomic"
 )
 
 /*
@@ -12,99 +13,242 @@ https://github.com/gorilla/mux/blob/master/LICENSE
 
 // create new router.
 func New() *Router {
-	return &Router{}
+	return &Router{
+		tree: newTree(),
+	}
 }
 
 type Router struct {
+	// parent router (nil if router is root).
+	parent *Router
+
 	// route groups.
 	groups []*Router
 
 	// routes.
 	routes []*Route
 
+	// groups and routes compiled to tree.
+	tree *node
+
 	// prefix info.
 	prefix prefixes
 
 	// allowed request methods.
 	allowedMethods []string
 
+	// request matchers (host, headers, queries, etc).
+	matchers []requestMatcher
+
 	// middleware chain.
 	middleware MiddlewareFunc
+
+	// how much middlewares in chain.
+	middlewareCount int
+
+	// middleware chain wrapped around router endpoint.
+	handler http.Handler
+
+	// named routes (root router only).
+	names map[string]*Route
+
+	// route names used by more than one route (root router only).
+	duplicateNames map[string]bool
+
+	// answer OPTIONS requests automatically (nil - same as parent).
+	autoOptions *bool
+
+	// CORS policy (nil - same as parent).
+	cors *CORS
+
+	// when route not found (nil - same as parent).
+	handler404 RouteHandler
+
+	// when request method not allowed (nil - same as parent).
+	handler405 RouteHandler
+
+	// when route endpoint returns error (nil - same as parent).
+	errorHandler ErrorHandler
+
+	// recover panics (root router only, see Recover).
+	isRecover bool
+
+	// gets recovered panics (nil - ReportPanic).
+	panicReporter PanicReporter
+
+	// how to handle not canonical request paths (root router only).
+	pathPolicy PathPolicy
+
+	// match request path by encoded pieces (root router only).
+	useEncodedPath bool
+
+	// 1 - router read-only (root router only).
+	frozen int32
+
+	// router that serves requests after Swap (root router only).
+	live atomic.Value
+}
+
+// get root router.
+func (r *Router) root() *Router {
+	var current = r
+	for current.parent != nil {
+		current = current.parent
+	}
+	return current
 }
 
 // any parents (routes or groups) should remove this exclude prefix from
 // request path to match request.
 func (r *Router) getExcludePrefix() (excludeCount int) {
-	if len(r.prefix.path) < 1 {
-		return 0
+	return r.prefix.getStripCount()
+}
+
+// is group without prefix and matchers (matches path only by its content)?
+func (r *Router) isTransparent() bool {
+	return len(r.prefix.pathSlice) < 1 && len(r.matchers) < 1
+}
+
+// get groups and routes tree. Creates tree if not exists.
+func (r *Router) getTree() *node {
+	if r.tree == nil {
+		r.tree = newTree()
 	}
-	var exclude = "/" + r.prefix.path
-	exclude = pathToStandart(exclude)
-	var excludeSlice = splitPath(exclude)
-	excludeCount = len(excludeSlice) + r.prefix.excludeCount
-	return
+	return r.tree
 }
 
 // when request coming.
 func (r *Router) ServeHTTP(response http.ResponseWriter, request *http.Request) {
-	// run middleware.
-	var isResponseSended = executeMiddleware(response, request, r.middleware)
-	if isResponseSended {
+	// routes replaced by Swap.
+	if r.parent == nil && r.serveLive(response, request) {
 		return
 	}
 
-	var matcher = routeMatcher{}
-	matcher.New(request)
+	if r.parent == nil && r.isRecover {
+		r.serveRecover(response, request)
+		return
+	}
+	r.serveEndpoint(response, request)
+}
 
-	// try to match groups first.
-	if r.groups != nil {
-		var matched, code = matcher.Groups(r.groups)
-		if code == 405 {
-			Handler405(response, request)
+// serve request by middleware or router endpoint.
+func (r *Router) serveEndpoint(response http.ResponseWriter, request *http.Request) {
+	// run middleware (if exists). Middleware calls endpoint by itself.
+	if r.handler != nil {
+		r.handler.ServeHTTP(response, request)
+		return
+	}
+	r.serve(response, request)
+}
+
+// router endpoint: match groups / routes and serve it.
+func (r *Router) serve(response http.ResponseWriter, request *http.Request) {
+	// group matched by parent: continue serving.
+	if found := getParams(request); found != nil && r.parent != nil {
+		if index := found.match.indexOf(r); index > 0 {
+			r.serveMatch(response, request, &found.match, index)
 			return
 		}
-		if matched != nil {
-			matched.ServeHTTP(response, request)
+	}
+
+	// redirect to canonical path (if policy set).
+	if r.parent == nil {
+		if canonical, isRedirect := r.pathPolicy.getRedirectPath(request.URL.EscapedPath()); isRedirect {
+			redirectToPath(response, request, canonical)
 			return
 		}
 	}
 
-	// try to match routes.
-	if r.routes != nil {
-		var matched, code = matcher.Routes(r.routes)
-		if code == 405 {
-			Handler405(response, request)
-			return
+	var matcher = getMatcher(request)
+	defer matcher.release()
+	matcher.Match(r)
+
+	// attach variables once, matched groups get them from request.
+	var match = &matcher.result
+	if len(matcher.vars) > 0 || len(match.chain) > 1 {
+		request = attachVars(request, match, matcher.vars)
+		match = &getParams(request).match
+	}
+	r.serveMatch(response, request, match, 0)
+}
+
+// serve matched request by router at index of match chain:
+// pass request to next matched group or serve route.
+func (r *Router) serveMatch(response http.ResponseWriter, request *http.Request, match *routeMatch, index int) {
+	if index < len(match.chain)-1 {
+		var next = match.chain[index+1]
+		r.setServing(request, next, nil)
+		next.ServeHTTP(response, request)
+		return
+	}
+
+	switch {
+	case match.notAllowedGroup != nil:
+		// group handler (like JSON errors for /api).
+		sendMethodNotAllowed(response, request, match.allowed, match.notAllowedGroup.getHandler405())
+	case len(match.candidates) > 0 && serveOptions(response, request, match.candidates):
+	case match.statusCode == 405:
+		sendMethodNotAllowed(response, request, match.allowed, r.getHandler405())
+	case match.route != nil:
+		var matched = match.route
+		r.setServing(request, nil, matched)
+		if policy := matched.getCORS(); policy != nil && !isPreflight(request) {
+			policy.setSimpleHeaders(response, request)
 		}
-		if matched != nil {
-			matched.ServeHTTP(response, request)
+		if match.isImplicitHead {
+			var headWriter = &headResponseWriter{ResponseWriter: response}
+			matched.ServeHTTP(headWriter, request)
+			headWriter.finish()
 			return
 		}
+		matched.ServeHTTP(response, request)
+	default:
+		r.getHandler404()(response, request)
 	}
-
-	// 404.
-	Handler404(response, request)
-	return
 }
 
 // add route.
 func (r *Router) Route(to string, handler RouteHandler) *Route {
-	if r.routes == nil {
-		r.routes = make([]*Route, 0)
-	}
+	return r.Handle(to, handler)
+}
+
+// add route with http.Handler endpoint
+// (like http.StripPrefix, httputil.ReverseProxy or handler struct).
+func (r *Router) Handle(to string, handler http.Handler) *Route {
+	r.checkFrozen()
 
 	// new route.
-	var newRoute = &Route{}
+	var newRoute = &Route{router: r}
 	var excludeCount = r.getExcludePrefix()
 	newRoute.new(excludeCount, to, handler)
+	if r.isStrictSlash() && isTrailingSlashPiece(to, newRoute.fullPathSlice()) {
+		// /users/ and /users are different routes.
+		newRoute.prefix.pathSlice = append(newRoute.prefix.pathSlice, "")
+	}
+	checkCatchAll(newRoute.prefix.pathSlice, false)
 
-	// add to routes.
-	r.routes = append(r.routes, newRoute)
+	r.addRoute(newRoute)
 	return newRoute
 }
 
+// add route to routes and tree.
+func (r *Router) addRoute(route *Route) {
+	if r.routes == nil {
+		r.routes = make([]*Route, 0)
+	}
+	r.routes = append(r.routes, route)
+	r.getTree().addRoute(route)
+}
+
 // add route group.
+//
+// Request served by group with longest matched prefix
+// (static pieces before variables), groups with same prefix - in registration order.
+// Group with empty prefix and matchers (Host, Headers, etc) matches any path,
+// without matchers - only paths of its routes / groups.
 func (r *Router) Group(prefix string) (group *Router) {
+	r.checkFrozen()
+
 	// make groups if not.
 	if r.groups == nil {
 		r.groups = make([]*Router, 0)
@@ -112,24 +256,87 @@ func (r *Router) Group(prefix string) (group *Router) {
 
 	// create new router.
 	var newRouter = New()
+	newRouter.parent = r
 	var excludeCount = r.getExcludePrefix()
 	newRouter.prefix.excludeCount = excludeCount
 	newRouter.prefix.setPath(prefix)
 	newRouter.prefix.setPathSlice()
+	checkCatchAll(newRouter.prefix.pathSlice, true)
 
 	// add to groups.
 	r.groups = append(r.groups, newRouter)
+	r.getTree().addGroup(newRouter)
 	return newRouter
 }
 
 // provide middleware.
 func (r *Router) Use(middleware ...MiddlewareFunc) *Router {
+	r.checkFrozen()
 	r.middleware = processMiddleware(r.middleware, middleware...)
+	r.middlewareCount += countMiddleware(middleware...)
+	r.handler = wrapMiddleware(r.middleware, http.HandlerFunc(r.serve))
 	return r
 }
 
 // add allowed request methods.
 func (r *Router) Methods(methods ...string) *Router {
+	r.checkFrozen()
 	r.allowedMethods = processAllowedMethods(r.allowedMethods, methods...)
 	return r
 }
+
+// answer OPTIONS requests with Allow header
+// if route not accepts OPTIONS by itself.
+//
+// Disabled by default. Groups and routes can override it.
+func (r *Router) AutoOptions(enabled bool) *Router {
+	r.checkFrozen()
+	r.autoOptions = &enabled
+	return r
+}
+
+// set CORS policy. Preflight requests answered automatically,
+// simple requests get CORS headers.
+//
+// Groups and routes can override it.
+func (r *Router) CORS(policy *CORS) *Router {
+	r.checkFrozen()
+	r.cors = policy
+	return r
+}
+
+// set 404 handler. Groups use handler of parent, if not set.
+func (r *Router) NotFound(handler RouteHandler) *Router {
+	r.checkFrozen()
+	r.handler404 = handler
+	return r
+}
+
+// set 405 handler. Groups use handler of parent, if not set.
+//
+// Allow header already set. Use AllowedMethods() to get allowed methods.
+func (r *Router) MethodNotAllowed(handler RouteHandler) *Router {
+	r.checkFrozen()
+	r.handler405 = handler
+	return r
+}
+
+// get 404 handler of router or its parents (or Handler404).
+func (r *Router) getHandler404() RouteHandler {
+	for current := r; current != nil; current = current.parent {
+		if current.handler404 != nil {
+			return current.handler404
+		}
+	}
+	return Handler404
+}
+
+// get 405 handler of router or its parents (or Handler405).
+func (r *Router) getHandler405() RouteHandler {
+	for current := r; current != nil; current = current.parent {
+		if current.handler405 != nil {
+			return current.handler405
+		}
+	}
+	return Handler405
+}
@@ -2,195 +2,293 @@ package goway
 
 import (
 	"net/http"
+	"strings"
+	"sync"
 )
 
-type routeMatcher struct {
-	request          *http.Request
-	requestPath      string
-	requestPathSlice []string
-	method           string
-}
+// result of request matching.
+//
+// Computed once per request by router that got request,
+// matched groups serve request by it (without matching path again).
+type routeMatch struct {
+	// routers from router that matched request to router that serves it.
+	chain []*Router
 
-func (r *routeMatcher) New(req *http.Request) {
-	r.request = req
-	r.method = r.request.Method
+	// matched route (nil if not matched).
+	route *Route
 
-	// convert request path to standart path, like we do it with route paths.
-	r.requestPath = pathToStandart(req.URL.Path)
-	r.requestPathSlice = splitPath(r.requestPath)
+	// HEAD request matched with GET route.
+	isImplicitHead bool
+
+	// 404/405 if route not matched.
+	statusCode int
+
+	// methods of groups / routes with matched path, but not allowed method.
+	allowed []string
+
+	// first group with matched prefix, but not allowed method.
+	notAllowedGroup *Router
+
+	// routes with matched path for OPTIONS request.
+	candidates []*Route
 }
 
-// match route group. Returns:
-//
-// group / statusCode 0
-//
-// nil / statusCode 404/405
-func (r *routeMatcher) Groups(routers []*Router) (matched *Router, statusCode int) {
-	statusCode = 200
-	var requestPath = r.requestPath
-	var requestPathSlice = r.requestPathSlice
-
-	for i := range routers {
-		// check is route has parent prefix.
-		var isHasExclude = routers[i].prefix.excludeCount > 0
-		if isHasExclude {
-			requestPathSlice = routers[i].prefix.getExcluded(requestPath)
+// get index of router in chain (-1 if not exists).
+func (m *routeMatch) indexOf(router *Router) int {
+	for i, current := range m.chain {
+		if current == router {
+			return i
 		}
+	}
+	return -1
+}
 
-		// example: prefix /hello/world, request /hello. Not our group.
-		if len(routers[i].prefix.pathSlice) > len(r.requestPathSlice) {
-			continue
-		}
+type routeMatcher struct {
+	request *http.Request
+	method  string
 
-		var isPiecesMatched = r.matchPathPieces(i, routers[i].prefix.pathSlice, requestPathSlice)
-		if isPiecesMatched {
-			matched = routers[i]
-		}
+	// variables of all matched levels (in lookup memory).
+	vars []routeVar
 
-		var isLastGroup = i == len(routers)-1
+	// memory for variables while tree lookup.
+	lookup []routeVar
 
-		// no match.
-		if matched == nil {
-			if !isLastGroup {
-				// not last group, continue searching.
-				continue
-			}
-			// change code to 404, but if before we not found match with unallowed method.
-			if statusCode != 405 {
-				statusCode = 404
-			}
-			return
-		}
+	// memory for result.
+	result routeMatch
+}
 
-		// match.
-		// check is method allowed.
-		var isAllowed = isMethodAllowed(matched.allowedMethods, r.method)
-		if isAllowed {
-			// it's our match.
-			statusCode = 0
-			return
-		}
-		// method not allowed.
-		statusCode = 405
-		if isLastGroup {
-			// method not allowed and no more groups.
-			matched = nil
-			return
+// memory for request matching, reused between requests.
+var matcherPool = sync.Pool{
+	New: func() interface{} {
+		return &routeMatcher{
+			lookup: make([]routeVar, 0, 8),
+			result: routeMatch{chain: make([]*Router, 0, 4)},
 		}
+	},
+}
+
+// get matcher from pool. Call release when request served.
+func getMatcher(request *http.Request) *routeMatcher {
+	var matcher = matcherPool.Get().(*routeMatcher)
+	matcher.request = request
+	matcher.method = request.Method
+	matcher.vars = matcher.lookup[:0]
+	return matcher
+}
+
+// put matcher back to pool. Variables and result can't be used after it.
+func (r *routeMatcher) release() {
+	for i := range r.result.chain {
+		r.result.chain[i] = nil
+	}
+	r.result = routeMatch{chain: r.result.chain[:0]}
+	if cap(r.vars) > cap(r.lookup) {
+		// keep grown memory.
+		r.lookup = r.vars
+	}
+	r.vars = nil
+	r.request = nil
+	matcherPool.Put(r)
+}
+
+// split request path to pieces after router prefix.
+func (r *routeMatcher) getPathSlice(router *Router) (pathSlice []string) {
+	var root = router.root()
 
-		// summary: group matched, but method not allowed
-		// group not last, try to find other group.
+	// encoded slashes (%2F) not split path.
+	var requestPath = r.request.URL.Path
+	if root.useEncodedPath {
+		requestPath = r.request.URL.EscapedPath()
+	}
+
+	// convert request path to standart path, like we do it with route paths.
+	var standart = pathToStandart(requestPath)
+	var excluder = prefixes{excludeCount: router.getExcludePrefix()}
+	pathSlice = excluder.getExcluded(standart)
+	if root.useEncodedPath {
+		unescapePieces(pathSlice)
+	}
+	if root.pathPolicy == PathStrict && len(standart) > 0 && strings.HasSuffix(requestPath, "/") {
+		pathSlice = append(pathSlice, "")
 	}
 	return
 }
 
-// match route. Returns:
-//
-// route / statusCode 0
-//
-// nil / statusCode 404/405
-func (r *routeMatcher) Routes(routes []*Route) (matched *Route, statusCode int) {
-	statusCode = 200
-	var requestPath = r.requestPath
-	var requestPathSlice = r.requestPathSlice
-	for i := range routes {
-		// check is route under group (has prefix).
-		if routes[i].isUnderGroup {
-			// remove prefix from request path (its already computed in group).
-			requestPathSlice = routes[i].prefix.getExcluded(requestPath)
-		}
+// match request by router (router prefix already matched)
+// and its groups. Result in r.result.
+func (r *routeMatcher) Match(router *Router) {
+	var pathSlice = r.getPathSlice(router)
 
-		// example: request /hello, route /hello/world. Not our route.
-		if len(routes[i].prefix.pathSlice) != len(requestPathSlice) {
-			continue
+	// root router matchers (groups matchers checked by parent).
+	if router.parent == nil && router.matchers != nil {
+		var vars, isMatched = r.checkMatchers(router.matchers)
+		if !isMatched {
+			r.result.chain = append(r.result.chain, router)
+			r.result.statusCode = 404
+			return
 		}
+		r.vars = append(r.vars, vars...)
+	}
+	r.match(router, pathSlice)
+}
+
+// match rest of request path by router.
+func (r *routeMatcher) match(router *Router, pathSlice []string) {
+	r.result.chain = append(r.result.chain, router)
 
-		var isPiecesMatched = r.matchPathPieces(i, routes[i].prefix.pathSlice, requestPathSlice)
-		if isPiecesMatched {
-			matched = routes[i]
+	// try to match groups first.
+	if router.groups != nil && r.Groups(router.tree, pathSlice) {
+		return
+	}
+
+	// try to match routes.
+	if router.routes != nil {
+		if r.method == http.MethodOptions {
+			r.result.candidates = r.RouteCandidates(router.tree, pathSlice)
 		}
+		r.Routes(router.tree, pathSlice)
+		return
+	}
+	r.result.statusCode = 404
+}
 
-		var isLastRoute = i == len(routes)-1
+// match route group and its content. Returns false if no group matched.
+//
+// Group with empty prefix and without matchers matches path
+// only if its routes / groups match it.
+func (r *routeMatcher) Groups(tree *node, pathSlice []string) (isMatched bool) {
+	var notAllowedGroup *Router
+	var allowed []string
+	var parentVars = r.vars
+	tree.lookupGroups(pathSlice, r.vars, func(found *node, vars []routeVar) bool {
+		for _, group := range found.groups {
+			// check host, headers, etc.
+			var matchersVars, ok = r.checkMatchers(group.matchers)
+			if !ok {
+				continue
+			}
 
-		// no match.
-		if matched == nil {
-			if !isLastRoute {
-				// not last route, continue searching.
+			// check is method allowed.
+			// OPTIONS can be answered by group routes.
+			var isAllowed = isMethodAllowed(group.allowedMethods, r.method) ||
+				isImplicitHead(group.allowedMethods, r.method) ||
+				(r.method == http.MethodOptions && (group.isAutoOptions() || group.getCORS() != nil))
+			if !isAllowed {
+				// group matched, but method not allowed.
+				// Try to find other group.
+				allowed = append(allowed, group.allowedMethods...)
+				if notAllowedGroup == nil {
+					notAllowedGroup = group
+				}
 				continue
 			}
-			// change code to 404, but if before we not found match with unallowed method.
-			if statusCode != 405 {
-				statusCode = 404
+			// it's our match: match rest of path by group.
+			var chainLen = len(r.result.chain)
+			r.vars = append(vars, matchersVars...)
+			r.match(group, pathSlice[len(group.prefix.pathSlice):])
+			if r.result.statusCode == 404 && group.isTransparent() {
+				// nothing in group: try other groups and routes.
+				for i := chainLen; i < len(r.result.chain); i++ {
+					r.result.chain[i] = nil
+				}
+				r.result = routeMatch{chain: r.result.chain[:chainLen]}
+				r.vars = parentVars
+				continue
 			}
-			return
+			isMatched = true
+			return true
 		}
+		return false
+	})
+	if !isMatched && notAllowedGroup != nil {
+		// served by group handler (like JSON errors for /api).
+		r.result.statusCode = 405
+		r.result.allowed = allowed
+		r.result.notAllowedGroup = notAllowedGroup
+		return true
+	}
+	return
+}
+
+// match route. Result in r.result: route or statusCode 404/405.
+func (r *routeMatcher) Routes(tree *node, pathSlice []string) {
+	r.result.statusCode = 404
 
-		// match.
-		// check is method allowed.
-		var isAllowed = isMethodAllowed(matched.allowedMethods, r.method)
-		if isAllowed {
+	// GET route for HEAD request (if HEAD route not exists).
+	var headFallback *Route
+	var headFallbackVars []routeVar
+
+	tree.lookupRoutes(pathSlice, r.vars, func(found *node, vars []routeVar) bool {
+		// routes with allowed method.
+		for _, route := range found.routesFor(r.method) {
+			// check host, headers, etc.
+			var matchersVars, isMatched = r.checkMatchers(route.matchers)
+			if !isMatched {
+				continue
+			}
 			// it's our match.
-			statusCode = 0
-			return
-		}
-		// method not allowed.
-		statusCode = 405
-		if isLastRoute {
-			// method not allowed and no more routes.
-			matched = nil
-			return
+			r.result.route = route
+			r.result.statusCode = 0
+			r.vars = append(vars, matchersVars...)
+			return true
 		}
 
-		// summary: route matched, but method not allowed
-		// route not last, try to find other route.
+		// path matched, but method not allowed.
+		for _, route := range found.routes {
+			if isMethodAllowed(route.allowedMethods, r.method) {
+				// matchers not matched.
+				continue
+			}
+			var matchersVars, isMatched = r.checkMatchers(route.matchers)
+			if !isMatched {
+				continue
+			}
+			if headFallback == nil && isImplicitHead(route.allowedMethods, r.method) {
+				// remember, but try to find HEAD route.
+				headFallback = route
+				headFallbackVars = append(headFallbackVars, vars...)
+				headFallbackVars = append(headFallbackVars, matchersVars...)
+				continue
+			}
+			if isImplicitHead(route.allowedMethods, r.method) {
+				continue
+			}
+			// try to find other route.
+			r.result.statusCode = 405
+			r.result.allowed = append(r.result.allowed, route.allowedMethods...)
+		}
+		return false
+	})
 
+	if r.result.route == nil && headFallback != nil {
+		r.result.route = headFallback
+		r.result.statusCode = 0
+		r.result.isImplicitHead = true
+		r.vars = headFallbackVars
 	}
-	return
 }
 
-// compare paths and add route vars to request context if exists.
-//
-// Matched == true examples:
-//
-// 1. pathSlice: ["api", "users", "{id}"], requestPathSlice ["api", "users", "12", "actions", "hello"]
-//
-// 2. pathSlice: ["api", "users", "12"], requestPathSlice ["api", "users", "12", "actions"]
-//
-// 3. pathSlice: ["api", "users"], requestPathSlice ["api", "users"]
-//
-// 4. pathSlice: [] or nil, requestPathSlice [] or nil
-func (r *routeMatcher) matchPathPieces(counter int, pathSlice []string, requestPathSlice []string) (matched bool) {
-	// match if paths empty.
-	if isPathSliceEmpty(pathSlice) && isPathSliceEmpty(requestPathSlice) {
-		return true
-	}
-
-	// compare paths.
-	for pieceCounter := range pathSlice {
-		var pathPiece = pathSlice[pieceCounter]
-		var requestPathPiece = requestPathSlice[pieceCounter]
-
-		var isSame = pathPiece == requestPathPiece
-		// not same? maybe it's route variable?
-		if !isSame {
-			var isVar, name = isRouteVar(pathPiece)
-			if !isVar {
-				// summary: pieces not same, and it's not var. Not our path.
-				matched = false
-				break
+// get all routes with matched path and matchers (in matching order).
+func (r *routeMatcher) RouteCandidates(tree *node, pathSlice []string) (candidates []*Route) {
+	tree.lookupRoutes(pathSlice, nil, func(found *node, vars []routeVar) bool {
+		for _, route := range found.routes {
+			if _, isMatched := r.checkMatchers(route.matchers); isMatched {
+				candidates = append(candidates, route)
 			}
-
-			// if it's var, add it to context
-			addVarToContext(r.request, name, requestPathPiece)
 		}
+		return false
+	})
+	return
+}
 
-		// check is last piece.
-		var isLast = pieceCounter == len(pathSlice)-1
-		if isLast {
-			// pieces same, maybe it's our path.
-			matched = true
-			return
+// check group / route matchers. Returns variables from matchers.
+func (r *routeMatcher) checkMatchers(matchers []requestMatcher) (vars []routeVar, isMatched bool) {
+	for _, matcher := range matchers {
+		var matcherVars, ok = matcher.match(r.request)
+		if !ok {
+			return nil, false
 		}
+		vars = append(vars, matcherVars...)
 	}
-	return
+	return vars, true
 }
@@ -0,0 +1,259 @@
+package goway
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// custom request matcher.
+type MatcherFunc func(request *http.Request) bool
+
+func (m MatcherFunc) match(request *http.Request) (vars []routeVar, isMatched bool) {
+	return nil, m(request)
+}
+
+// match request headers by values.
+//
+// Empty value - header should exist.
+type headersMatcher map[string]string
+
+func (h headersMatcher) match(request *http.Request) (vars []routeVar, isMatched bool) {
+	for key, value := range h {
+		var values, ok = request.Header[key]
+		if !ok {
+			return nil, false
+		}
+		if len(value) < 1 {
+			continue
+		}
+		var isFound = false
+		for _, headerValue := range values {
+			if headerValue == value {
+				isFound = true
+				break
+			}
+		}
+		if !isFound {
+			return nil, false
+		}
+	}
+	return nil, true
+}
+
+// match request headers by regular expressions.
+type headersRegexpMatcher map[string]*regexp.Regexp
+
+func (h headersRegexpMatcher) match(request *http.Request) (vars []routeVar, isMatched bool) {
+	for key, expr := range h {
+		var values, ok = request.Header[key]
+		if !ok {
+			return nil, false
+		}
+		var isFound = false
+		for _, headerValue := range values {
+			if expr.MatchString(headerValue) {
+				isFound = true
+				break
+			}
+		}
+		if !isFound {
+			return nil, false
+		}
+	}
+	return nil, true
+}
+
+// match query parameter.
+type queryMatcher struct {
+	// parameter name.
+	key string
+
+	// parameter value. Empty - parameter should exist.
+	value string
+
+	// if value is variable (like {page}) - variable name.
+	varName string
+
+	// if value is variable - variable constraint (if exists).
+	constraint *regexp.Regexp
+}
+
+func newQueryMatcher(key string, value string) *queryMatcher {
+	var matcher = &queryMatcher{key: key, value: value}
+	var isVar, name, constraint = parseRouteVar(value)
+	if isVar {
+		matcher.varName = name
+		matcher.constraint = compileConstraint(constraint)
+	}
+	return matcher
+}
+
+func (q *queryMatcher) match(request *http.Request) (vars []routeVar, isMatched bool) {
+	var values, ok = request.URL.Query()[q.key]
+	if !ok || len(values) < 1 {
+		return nil, false
+	}
+	var value = values[0]
+	if len(q.varName) > 0 {
+		if q.constraint != nil && !q.constraint.MatchString(value) {
+			return nil, false
+		}
+		return []routeVar{{name: q.varName, value: value}}, true
+	}
+	if len(q.value) > 0 && q.value != value {
+		return nil, false
+	}
+	return nil, true
+}
+
+// match request URL scheme.
+type schemesMatcher []string
+
+func (s schemesMatcher) match(request *http.Request) (vars []routeVar, isMatched bool) {
+	var scheme = request.URL.Scheme
+	if len(scheme) < 1 {
+		scheme = "http"
+		if request.TLS != nil {
+			scheme = "https"
+		}
+	}
+	for _, allowed := range s {
+		if strings.EqualFold(allowed, scheme) {
+			return nil, true
+		}
+	}
+	return nil, false
+}
+
+// convert pairs like: "key", "value", "key2", "value2" to map.
+//
+// Panics if number of pairs is odd.
+func mapFromPairs(pairs ...string) map[string]string {
+	if len(pairs)%2 != 0 {
+		panic("goway: odd number of pairs")
+	}
+	var result = make(map[string]string, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		result[pairs[i]] = pairs[i+1]
+	}
+	return result
+}
+
+func newHeadersMatcher(pairs ...string) headersMatcher {
+	var matcher = headersMatcher{}
+	for key, value := range mapFromPairs(pairs...) {
+		matcher[http.CanonicalHeaderKey(key)] = value
+	}
+	return matcher
+}
+
+func newHeadersRegexpMatcher(pairs ...string) headersRegexpMatcher {
+	var matcher = headersRegexpMatcher{}
+	for key, expr := range mapFromPairs(pairs...) {
+		var compiled, err = regexp.Compile(expr)
+		if err != nil {
+			panic("goway: invalid header expression: " + expr + ": " + err.Error())
+		}
+		matcher[http.CanonicalHeaderKey(key)] = compiled
+	}
+	return matcher
+}
+
+func newQueryMatchers(pairs ...string) []requestMatcher {
+	// check pairs.
+	mapFromPairs(pairs...)
+	var matchers = make([]requestMatcher, 0)
+	for i := 0; i < len(pairs); i += 2 {
+		matchers = append(matchers, newQueryMatcher(pairs[i], pairs[i+1]))
+	}
+	return matchers
+}
+
+// match group only on requests with this headers.
+//
+// pairs - header name and value like: "X-Event-Type", "push".
+// Empty value - header should exist.
+func (r *Router) Headers(pairs ...string) *Router {
+	r.checkFrozen()
+	r.matchers = append(r.matchers, newHeadersMatcher(pairs...))
+	return r
+}
+
+// match group only on requests with headers matching regular expressions.
+//
+// pairs - header name and expression like: "Accept", "application/vnd\\.api\\.v1".
+func (r *Router) HeadersRegexp(pairs ...string) *Router {
+	r.checkFrozen()
+	r.matchers = append(r.matchers, newHeadersRegexpMatcher(pairs...))
+	return r
+}
+
+// match group only on requests with this query parameters.
+//
+// pairs - parameter name and value like: "page", "{page:int}".
+// Empty value - parameter should exist.
+// Variable value (like {page}) available in Vars().
+func (r *Router) Queries(pairs ...string) *Router {
+	r.checkFrozen()
+	r.matchers = append(r.matchers, newQueryMatchers(pairs...)...)
+	return r
+}
+
+// match group only on requests with this URL schemes (like: https).
+func (r *Router) Schemes(schemes ...string) *Router {
+	r.checkFrozen()
+	r.matchers = append(r.matchers, schemesMatcher(schemes))
+	return r
+}
+
+// match group only if matcher returns true.
+func (r *Router) MatcherFunc(matcher MatcherFunc) *Router {
+	r.checkFrozen()
+	r.matchers = append(r.matchers, matcher)
+	return r
+}
+
+// match route only on requests with this headers.
+//
+// pairs - header name and value like: "X-Event-Type", "push".
+// Empty value - header should exist.
+func (r *Route) Headers(pairs ...string) *Route {
+	r.checkFrozen()
+	r.matchers = append(r.matchers, newHeadersMatcher(pairs...))
+	return r
+}
+
+// match route only on requests with headers matching regular expressions.
+//
+// pairs - header name and expression like: "Accept", "application/vnd\\.api\\.v1".
+func (r *Route) HeadersRegexp(pairs ...string) *Route {
+	r.checkFrozen()
+	r.matchers = append(r.matchers, newHeadersRegexpMatcher(pairs...))
+	return r
+}
+
+// match route only on requests with this query parameters.
+//
+// pairs - parameter name and value like: "page", "{page:int}".
+// Empty value - parameter should exist.
+// Variable value (like {page}) available in Vars().
+func (r *Route) Queries(pairs ...string) *Route {
+	r.checkFrozen()
+	r.matchers = append(r.matchers, newQueryMatchers(pairs...)...)
+	return r
+}
+
+// match route only on requests with this URL schemes (like: https).
+func (r *Route) Schemes(schemes ...string) *Route {
+	r.checkFrozen()
+	r.matchers = append(r.matchers, schemesMatcher(schemes))
+	return r
+}
+
+// match route only if matcher returns true.
+func (r *Route) MatcherFunc(matcher MatcherFunc) *Route {
+	r.checkFrozen()
+	r.matchers = append(r.matchers, matcher)
+	return r
+}
@@ -0,0 +1,77 @@
+package goway
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouting_Matchers(t *testing.T) {
+	var root = New()
+	var newHandler = func(body string) RouteHandler {
+		return func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, body, Vars(r)["page"])
+		}
+	}
+	root.Route("/webhook", newHandler("push")).Headers("x-event-type", "push")
+	root.Route("/webhook", newHandler("any event")).Headers("X-Event-Type", "")
+	var v2 = root.Group("/api").HeadersRegexp("Accept", `application/vnd\.api\.v2`)
+	v2.Route("/users", newHandler("v2 users"))
+	var v1 = root.Group("/api")
+	v1.Route("/users", newHandler("v1 users page ")).Queries("page", "{page:int}")
+	v1.Route("/users", newHandler("v1 users"))
+	root.Route("/secure", newHandler("secure")).Schemes("https")
+	root.Route("/custom", newHandler("custom")).MatcherFunc(func(r *http.Request) bool {
+		return r.Header.Get("X-Custom") == "1"
+	})
+
+	type caser struct {
+		num      int
+		path     string
+		headers  map[string]string
+		isTLS    bool
+		expected string
+	}
+	var cases = []caser{
+		{num: 1, path: "/webhook", headers: map[string]string{"X-Event-Type": "push"}, expected: "push"},
+		{num: 2, path: "/webhook", headers: map[string]string{"X-Event-Type": "issue"}, expected: "any event"},
+		{num: 3, path: "/webhook", expected: "not found"},
+		{num: 4, path: "/api/users", headers: map[string]string{"Accept": "application/vnd.api.v2+json"}, expected: "v2 users"},
+		{num: 5, path: "/api/users?page=2", expected: "v1 users page 2"},
+		{num: 6, path: "/api/users?page=two", expected: "v1 users"},
+		{num: 7, path: "/secure", isTLS: true, expected: "secure"},
+		{num: 8, path: "/secure", expected: "not found"},
+		{num: 9, path: "/custom", headers: map[string]string{"X-Custom": "1"}, expected: "custom"},
+		{num: 10, path: "/custom", expected: "not found"},
+	}
+	var oldHandler = Handler404
+	Handler404 = getDefaultHandler404()
+	defer func() {
+		Handler404 = oldHandler
+	}()
+	for _, cased := range cases {
+		var req = httptest.NewRequest(http.MethodGet, cased.path, nil)
+		for key, value := range cased.headers {
+			req.Header.Set(key, value)
+		}
+		if cased.isTLS {
+			req.TLS = &tls.ConnectionState{}
+		}
+		var rec = httptest.NewRecorder()
+		root.ServeHTTP(rec, req)
+		if rec.Body.String() != cased.expected {
+			t.Fatalf("case num: %v | expected body: %v | got: %v", cased.num, cased.expected, rec.Body.String())
+		}
+	}
+}
+
+func TestMapFromPairs(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("odd pairs should panic")
+		}
+	}()
+	mapFromPairs("key")
+}
@@ -0,0 +1,58 @@
+package goway
+
+import (
+	"net/http"
+)
+
+// add route for request method (like: GET).
+//
+// Routes with same path and different methods can be added:
+// request served by route with request method, other methods get 405.
+func (r *Router) Method(method string, to string, handler RouteHandler) *Route {
+	return r.Route(to, handler).Methods(method)
+}
+
+// add route with http.Handler endpoint for request method (see Method).
+func (r *Router) MethodHandler(method string, to string, handler http.Handler) *Route {
+	return r.Handle(to, handler).Methods(method)
+}
+
+// add GET route (HEAD requests served by it too, if HEAD route not exists).
+func (r *Router) Get(to string, handler RouteHandler) *Route {
+	return r.Method(http.MethodGet, to, handler)
+}
+
+// add POST route.
+func (r *Router) Post(to string, handler RouteHandler) *Route {
+	return r.Method(http.MethodPost, to, handler)
+}
+
+// add PUT route.
+func (r *Router) Put(to string, handler RouteHandler) *Route {
+	return r.Method(http.MethodPut, to, handler)
+}
+
+// add PATCH route.
+func (r *Router) Patch(to string, handler RouteHandler) *Route {
+	return r.Method(http.MethodPatch, to, handler)
+}
+
+// add DELETE route.
+func (r *Router) Delete(to string, handler RouteHandler) *Route {
+	return r.Method(http.MethodDelete, to, handler)
+}
+
+// add OPTIONS route.
+func (r *Router) Options(to string, handler RouteHandler) *Route {
+	return r.Method(http.MethodOptions, to, handler)
+}
+
+// add HEAD route.
+func (r *Router) Head(to string, handler RouteHandler) *Route {
+	return r.Method(http.MethodHead, to, handler)
+}
+
+// add route for any method (same as Route).
+func (r *Router) Any(to string, handler RouteHandler) *Route {
+	return r.Route(to, handler)
+}
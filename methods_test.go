@@ -0,0 +1,154 @@
+package goway
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter_MethodRoutes(t *testing.T) {
+	var handler = func(response string) RouteHandler {
+		return func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, response)
+		}
+	}
+	var root = New().MethodNotAllowed(getDefaultHandler405())
+	root.Get("/users/{id}", handler("get"))
+	root.Post("/users/{id}", handler("post"))
+	root.Put("/users/{id}", handler("put"))
+	root.Patch("/users/{id}", handler("patch"))
+	root.Delete("/users/{id}", handler("delete"))
+	root.Options("/users/{id}", handler("options"))
+	root.Method("report", "/users/{id}", handler("report"))
+	root.Head("/files", handler("head"))
+	root.Get("/files", handler("files"))
+	root.Any("/any", handler("any"))
+	root.Get("/any", handler("get any"))
+	root.Route("/late", handler("late")).Methods(http.MethodPut)
+
+	type caser struct {
+		num      int
+		method   string
+		path     string
+		status   int
+		expected string
+		allow    string
+	}
+	var cases = []caser{
+		{num: 1, method: http.MethodGet, path: "/users/1", status: 200, expected: "get"},
+		{num: 2, method: http.MethodPost, path: "/users/1", status: 200, expected: "post"},
+		{num: 3, method: http.MethodPut, path: "/users/1", status: 200, expected: "put"},
+		{num: 4, method: http.MethodPatch, path: "/users/1", status: 200, expected: "patch"},
+		{num: 5, method: http.MethodDelete, path: "/users/1", status: 200, expected: "delete"},
+		{num: 6, method: http.MethodOptions, path: "/users/1", status: 200, expected: "options"},
+		{num: 7, method: "REPORT", path: "/users/1", status: 200, expected: "report"},
+		{num: 8, method: "PURGE", path: "/users/1", status: 405, expected: "method not allowed",
+			allow: "GET, POST, PUT, PATCH, DELETE, OPTIONS, REPORT, HEAD"},
+		// HEAD by GET route.
+		{num: 9, method: http.MethodHead, path: "/users/1", status: 200},
+		// explicit HEAD route.
+		{num: 10, method: http.MethodHead, path: "/files", status: 200, expected: "head"},
+		// first registered route wins.
+		{num: 11, method: http.MethodGet, path: "/any", status: 200, expected: "any"},
+		{num: 12, method: "PURGE", path: "/any", status: 200, expected: "any"},
+		// methods set after route added.
+		{num: 13, method: http.MethodPut, path: "/late", status: 200, expected: "late"},
+		{num: 14, method: http.MethodGet, path: "/late", status: 405, expected: "method not allowed", allow: "PUT"},
+	}
+	for _, c := range cases {
+		var recorder = httptest.NewRecorder()
+		root.ServeHTTP(recorder, httptest.NewRequest(c.method, c.path, nil))
+		if recorder.Code != c.status {
+			t.Fatalf("case num: %v | expected status: %v | got: %v", c.num, c.status, recorder.Code)
+		}
+		if body := recorder.Body.String(); body != c.expected {
+			t.Fatalf("case num: %v | expected: %v | got: %v", c.num, c.expected, body)
+		}
+		if allow := recorder.Header().Get("Allow"); allow != c.allow {
+			t.Fatalf("case num: %v | expected allow: %v | got: %v", c.num, c.allow, allow)
+		}
+	}
+}
+
+// handler struct.
+type greeter struct {
+	greeting string
+}
+
+func (g *greeter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, g.greeting, " ", Param(r, "name"), " ", r.URL.Path)
+}
+
+func TestRouter_Handle(t *testing.T) {
+	var executedMiddlewares = 0
+	var middleware = func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			executedMiddlewares++
+			next.ServeHTTP(w, r)
+		})
+	}
+	var root = New()
+	root.Handle("/hello/{name}", &greeter{greeting: "hello"}).Use(middleware)
+	root.MethodHandler(http.MethodPost, "/bye/{name}", &greeter{greeting: "bye"})
+	root.Handle("/strip/{name}", http.StripPrefix("/strip", &greeter{greeting: "stripped"}))
+	root.NotFound((&greeter{greeting: "not found"}).ServeHTTP)
+
+	type caser struct {
+		num      int
+		method   string
+		path     string
+		status   int
+		expected string
+	}
+	var cases = []caser{
+		{num: 1, method: http.MethodGet, path: "/hello/bob", status: 200, expected: "hello bob /hello/bob"},
+		{num: 2, method: http.MethodPost, path: "/bye/bob", status: 200, expected: "bye bob /bye/bob"},
+		{num: 3, method: http.MethodGet, path: "/bye/bob", status: 405, expected: "method not allowed"},
+		{num: 4, method: http.MethodGet, path: "/strip/bob", status: 200, expected: "stripped bob /bob"},
+		{num: 5, method: http.MethodGet, path: "/other", status: 200, expected: "not found  /other"},
+	}
+	var oldHandler = Handler405
+	Handler405 = getDefaultHandler405()
+	defer func() {
+		Handler405 = oldHandler
+	}()
+	for _, c := range cases {
+		var recorder = httptest.NewRecorder()
+		root.ServeHTTP(recorder, httptest.NewRequest(c.method, c.path, nil))
+		if recorder.Code != c.status || recorder.Body.String() != c.expected {
+			t.Fatalf("case num: %v | expected: %v %v | got: %v %v", c.num, c.status, c.expected, recorder.Code, recorder.Body.String())
+		}
+	}
+	if executedMiddlewares != 1 {
+		t.Fatalf("expected 1 middleware call | got: %v", executedMiddlewares)
+	}
+}
+
+func TestRouter_ImplicitHeadFlush(t *testing.T) {
+	var isFlusher, isUnwrapped bool
+	var root = New()
+	root.Get("/stream", func(w http.ResponseWriter, r *http.Request) {
+		var flusher, ok = w.(http.Flusher)
+		isFlusher = ok
+		var unwrapper, _ = w.(interface{ Unwrap() http.ResponseWriter })
+		isUnwrapped = unwrapper != nil && unwrapper.Unwrap() != nil
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event")
+		if ok {
+			flusher.Flush()
+		}
+		fmt.Fprint(w, "more")
+	})
+	var recorder = httptest.NewRecorder()
+	root.ServeHTTP(recorder, httptest.NewRequest(http.MethodHead, "/stream", nil))
+	if !isFlusher || !isUnwrapped {
+		t.Fatalf("expected Flush and Unwrap on HEAD writer | got: %v %v", isFlusher, isUnwrapped)
+	}
+	if !recorder.Flushed || recorder.Code != 200 || recorder.Body.Len() > 0 {
+		t.Fatalf("expected flushed headers without body | got: %v %v %q", recorder.Flushed, recorder.Code, recorder.Body.String())
+	}
+	if recorder.Header().Get("Content-Type") != "text/event-stream" || len(recorder.Header().Get("Content-Length")) > 0 {
+		t.Fatalf("unexpected headers: %v", recorder.Header())
+	}
+}
@@ -0,0 +1,99 @@
+package goway
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// tree piece for mounted handlers: catch-all without variable.
+const mountPiece = "{...}"
+
+// mount handler (like http.FileServer or pprof) under prefix.
+//
+// Handler receives all requests under prefix (any methods, if not set).
+// Prefix removed from request path, like in http.StripPrefix:
+// /static/css/main.css -> /css/main.css.
+//
+// Middleware of router (and its parents) applied to handler.
+func (r *Router) Mount(prefix string, handler http.Handler) *Route {
+	r.checkFrozen()
+
+	// new route.
+	var mounted = &Route{router: r, isMount: true}
+	var excludeCount = r.getExcludePrefix()
+	mounted.new(excludeCount, prefix, nil)
+	checkCatchAll(mounted.prefix.pathSlice, true)
+	var stripCount = mounted.prefix.getStripCount()
+	mounted.handler = RouteHandler(func(response http.ResponseWriter, request *http.Request) {
+		var isEncoded = mounted.router.root().useEncodedPath
+		handler.ServeHTTP(response, stripRequestPath(request, stripCount, isEncoded))
+	})
+
+	r.addRoute(mounted)
+	return mounted
+}
+
+// copy request with stripCount pieces removed from URL path.
+//
+// isEncoded - pieces counted in encoded path (see Router.UseEncodedPath).
+func stripRequestPath(request *http.Request, stripCount int, isEncoded bool) *http.Request {
+	var stripped = new(http.Request)
+	*stripped = *request
+	stripped.URL = new(url.URL)
+	*stripped.URL = *request.URL
+
+	if isEncoded {
+		// encoded slash (%2F) is part of piece.
+		var rawPath = stripPathPieces(cleanKeepSlash(request.URL.EscapedPath()), stripCount)
+		if unescaped, err := url.PathUnescape(rawPath); err == nil {
+			stripped.URL.Path = unescaped
+			stripped.URL.RawPath = rawPath
+			return stripped
+		}
+	}
+
+	// clean path like we do it when matching, but keep trailing slash.
+	stripped.URL.Path = stripPathPieces(cleanKeepSlash(request.URL.Path), stripCount)
+
+	// keep encoding if possible.
+	stripped.URL.RawPath = ""
+	if len(request.URL.RawPath) > 0 {
+		var rawPath = stripPathPieces(request.URL.RawPath, stripCount)
+		var unescaped, err = url.PathUnescape(rawPath)
+		if err == nil && unescaped == stripped.URL.Path {
+			stripped.URL.RawPath = rawPath
+		}
+	}
+	return stripped
+}
+
+// clean path like we do it when matching, but keep trailing slash.
+func cleanKeepSlash(p string) string {
+	var cleaned = path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// remove count pieces from start of path.
+//
+// example: path /static/css/main.css, count 1: /css/main.css
+func stripPathPieces(p string, count int) string {
+	var rest = p
+	for i := 0; i < count; i++ {
+		rest = strings.TrimLeft(rest, "/")
+		var slash = strings.Index(rest, "/")
+		if slash < 0 {
+			rest = ""
+			break
+		}
+		rest = rest[slash:]
+	}
+	if !strings.HasPrefix(rest, "/") {
+		rest = "/" + rest
+	}
+	return rest
+}
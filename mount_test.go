@@ -0,0 +1,97 @@
+package goway
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStripPathPieces(t *testing.T) {
+	type caser struct {
+		num      int
+		path     string
+		count    int
+		expected string
+	}
+	var cases = []caser{
+		{num: 1, path: "/static/css/main.css", count: 1, expected: "/css/main.css"},
+		{num: 2, path: "/static/css/", count: 1, expected: "/css/"},
+		{num: 3, path: "/static", count: 1, expected: "/"},
+		{num: 4, path: "//api//v1/users", count: 2, expected: "/users"},
+		{num: 5, path: "/static/a", count: 0, expected: "/static/a"},
+	}
+	for _, cased := range cases {
+		var result = stripPathPieces(cased.path, cased.count)
+		if result != cased.expected {
+			t.Fatalf("case num: %v | expected: %v | got: %v", cased.num, cased.expected, result)
+		}
+	}
+}
+
+func TestRouting_Mount(t *testing.T) {
+	var isMiddlewareExecuted = false
+	var root = New()
+	var api = root.Group("/api/{tenant}")
+	api.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			isMiddlewareExecuted = true
+			next.ServeHTTP(w, r)
+		})
+	})
+	api.Mount("/files", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, Vars(r)["tenant"], " ", r.URL.Path, " ", r.URL.RawPath)
+	}))
+	api.Route("/files/info", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "info")
+	})
+
+	type caser struct {
+		num      int
+		path     string
+		expected string
+	}
+	var cases = []caser{
+		{num: 1, path: "/api/oklookat/files/a/b.txt", expected: "oklookat /a/b.txt "},
+		{num: 2, path: "/api/oklookat/files/a/", expected: "oklookat /a/ "},
+		{num: 3, path: "/api/oklookat/files", expected: "oklookat / "},
+		{num: 4, path: "/api/oklookat/files/a%2Fb", expected: "oklookat /a/b /a%2Fb"},
+		{num: 5, path: "/api/oklookat/files/info", expected: "info"},
+	}
+	for _, cased := range cases {
+		isMiddlewareExecuted = false
+		var req = httptest.NewRequest(http.MethodGet, cased.path, nil)
+		var rec = httptest.NewRecorder()
+		root.ServeHTTP(rec, req)
+		if rec.Body.String() != cased.expected {
+			t.Fatalf("case num: %v | expected body: %v | got: %v", cased.num, cased.expected, rec.Body.String())
+		}
+		if !isMiddlewareExecuted {
+			t.Fatalf("case num: %v | group middleware not executed", cased.num)
+		}
+	}
+}
+
+func TestRouting_MountEncodedPath(t *testing.T) {
+	var root = New().UseEncodedPath()
+	root.Mount("/files/{bucket}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, Param(r, "bucket"), " ", r.URL.Path, " ", r.URL.EscapedPath())
+	}))
+	type caser struct {
+		num      int
+		path     string
+		expected string
+	}
+	var cases = []caser{
+		{num: 1, path: "/files/a%2Fb/c.txt", expected: "a/b /c.txt /c.txt"},
+		{num: 2, path: "/files/a/dir%2Fname/c.txt", expected: "a /dir/name/c.txt /dir%2Fname/c.txt"},
+		{num: 3, path: "/files/a/", expected: "a / /"},
+	}
+	for _, cased := range cases {
+		var rec = httptest.NewRecorder()
+		root.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, cased.path, nil))
+		if rec.Body.String() != cased.expected {
+			t.Fatalf("case num: %v | expected: %v | got: %v", cased.num, cased.expected, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package goway
+
+import (
+	"context"
+	"net/http"
+)
+
+// route variables of request and result of request matching.
+//
+// Attached to request context once per request (when request matched),
+// matched groups serve request by it. Not changed after attaching:
+// new variables attached as new params.
+//
+// Not reused between requests: handler can read variables
+// after router returned (like in http.TimeoutHandler or goroutines).
+type params struct {
+	vars []routeVar
+
+	// how request matched (see routeMatch).
+	match routeMatch
+
+	// memory for few variables and matched routers (no extra allocations).
+	varsBuf  [3]routeVar
+	chainBuf [4]*Router
+}
+
+// get route variable value. Last variable with name wins.
+func (p *params) get(name string) (value string, ok bool) {
+	for i := len(p.vars) - 1; i >= 0; i-- {
+		if p.vars[i].name == name {
+			return p.vars[i].value, true
+		}
+	}
+	return
+}
+
+// get request params (nil if not exists).
+func getParams(request *http.Request) *params {
+	var found, _ = request.Context().Value(CTX_VARS_NAME).(*params)
+	return found
+}
+
+// attach matched variables and matching result to request.
+//
+// Returns request with new params: variables of existing params copied,
+// existing params not changed.
+func attachVars(request *http.Request, match *routeMatch, vars []routeVar) (withVars *http.Request) {
+	var existing = getParams(request)
+	var created = &params{}
+	created.vars = created.varsBuf[:0]
+	if existing != nil {
+		created.vars = append(created.vars, existing.vars...)
+		created.match = existing.match
+	}
+	created.vars = append(created.vars, vars...)
+	if match != nil {
+		created.match = *match
+		created.match.chain = append(created.chainBuf[:0], match.chain...)
+		if match.allowed != nil {
+			created.match.allowed = append([]string(nil), match.allowed...)
+		}
+	}
+	var ctx = context.WithValue(request.Context(), CTX_VARS_NAME, created)
+	return request.WithContext(ctx)
+}
+
+// get route variable value. Empty if not exists.
+//
+// Unlike Vars, not allocates.
+func Param(request *http.Request, name string) string {
+	var found = getParams(request)
+	if found == nil {
+		return ""
+	}
+	var value, _ = found.get(name)
+	return value
+}
@@ -0,0 +1,175 @@
+package goway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParam(t *testing.T) {
+	var root = New()
+	var api = root.Group("/api/{version}")
+	var inHandler = make(map[string]string)
+	api.Route("/users/{id}/posts/{post}", func(w http.ResponseWriter, r *http.Request) {
+		for _, name := range []string{"version", "id", "post", "notExists"} {
+			inHandler[name] = Param(r, name)
+		}
+		fmt.Fprint(w, Vars(r))
+	})
+	var recorder = httptest.NewRecorder()
+	root.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/users/12/posts/3", nil))
+
+	var expected = map[string]string{"version": "v1", "id": "12", "post": "3", "notExists": ""}
+	for name, value := range expected {
+		if inHandler[name] != value {
+			t.Fatalf("var: %v | expected: %v | got: %v", name, value, inHandler[name])
+		}
+	}
+	if body := recorder.Body.String(); body != "map[id:12 post:3 version:v1]" {
+		t.Fatalf("expected vars: map[id:12 post:3 version:v1] | got: %v", body)
+	}
+
+	// last variable wins.
+	var request = httptest.NewRequest(http.MethodGet, "/", nil)
+	addVarToContext(request, "id", "1")
+	addVarToContext(request, "id", "2")
+	if value := Param(request, "id"); value != "2" {
+		t.Fatalf("expected: 2 | got: %v", value)
+	}
+
+	// request without variables.
+	if value := Param(httptest.NewRequest(http.MethodGet, "/", nil), "id"); len(value) > 0 {
+		t.Fatalf("expected empty | got: %v", value)
+	}
+}
+
+// handler can read variables after router returned.
+func TestParam_AfterServe(t *testing.T) {
+	var release = make(chan struct{})
+	var result = make(chan string, 1)
+	var slow = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if Param(r, "id") != "first" {
+			return
+		}
+		<-release
+		result <- fmt.Sprint(Param(r, "id"), " ", Vars(r)["id"])
+	})
+	var root = New()
+	root.Handle("/slow/{id}", http.TimeoutHandler(slow, 10*time.Millisecond, "timeout"))
+	root.Route("/fast/{id}", func(w http.ResponseWriter, r *http.Request) {})
+
+	var recorder = httptest.NewRecorder()
+	root.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/slow/first", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected timeout | got: %v", recorder.Code)
+	}
+	// next requests should not change variables of first request.
+	for i := 0; i < 10; i++ {
+		root.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fast/second", nil))
+		root.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow/second", nil))
+	}
+	close(release)
+	if value := <-result; value != "first first" {
+		t.Fatalf("expected: first first | got: %v", value)
+	}
+}
+
+// params not changed after attaching (goroutines of middleware read them).
+func TestParam_Immutable(t *testing.T) {
+	var result = make(chan string, 1)
+	var root = New()
+	var api = root.Group("/api/{version}").Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var vars = getParams(r).vars
+			go func() {
+				result <- fmt.Sprint(len(vars), " ", Param(r, "version"))
+			}()
+			next.ServeHTTP(w, r)
+		})
+	})
+	api.Group("/users/{id}").Route("/posts/{post}", func(w http.ResponseWriter, r *http.Request) {
+		var withExtra = *r
+		addVarToContext(&withExtra, "extra", "1")
+		fmt.Fprint(w, len(Param(r, "extra")), " ", Param(&withExtra, "extra"), Param(&withExtra, "post"))
+	})
+	var recorder = httptest.NewRecorder()
+	root.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/users/12/posts/3", nil))
+	if body := recorder.Body.String(); body != "0 13" {
+		t.Fatalf("expected: 0 13 | got: %v", body)
+	}
+	if value := <-result; value != "3 v1" {
+		t.Fatalf("expected: 3 v1 | got: %v", value)
+	}
+}
+
+func TestParam_Allocs(t *testing.T) {
+	var request = httptest.NewRequest(http.MethodGet, "/", nil)
+	addVarToContext(request, "id", "12")
+	var allocs = testing.AllocsPerRun(100, func() {
+		Param(request, "id")
+	})
+	if allocs > 0 {
+		t.Fatalf("expected 0 allocations | got: %v", allocs)
+	}
+}
+
+// old way: every variable copies request.
+func legacyAddVarToContext(request *http.Request, name string, value string) {
+	var oldCtx = request.Context()
+	var varsMap, ok = oldCtx.Value(CTX_VARS_NAME).(map[string]string)
+	if !ok || varsMap == nil {
+		varsMap = make(map[string]string, 0)
+	}
+	varsMap[name] = value
+	var ctxWithVars = context.WithValue(oldCtx, CTX_VARS_NAME, varsMap)
+	*request = *request.WithContext(ctxWithVars)
+}
+
+var benchVars = []routeVar{{name: "version", value: "v1"}, {name: "id", value: "12"}, {name: "post", value: "3"}}
+
+func BenchmarkVars_Legacy(b *testing.B) {
+	var base = httptest.NewRequest(http.MethodGet, "/", nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var request = *base
+		for _, v := range benchVars {
+			legacyAddVarToContext(&request, v.name, v.value)
+		}
+	}
+}
+
+func BenchmarkVars_Params(b *testing.B) {
+	var base = httptest.NewRequest(http.MethodGet, "/", nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var request = *base
+		attachVars(&request, nil, benchVars)
+	}
+}
+
+func BenchmarkParam(b *testing.B) {
+	var request = attachVars(httptest.NewRequest(http.MethodGet, "/", nil), nil, benchVars)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Param(request, "post")
+	}
+}
+
+func BenchmarkServe_Vars(b *testing.B) {
+	var root = New()
+	var api = root.Group("/api/{version}")
+	api.Route("/users/{id}/posts/{post}", func(w http.ResponseWriter, r *http.Request) {
+		Param(r, "id")
+	})
+	var base = httptest.NewRequest(http.MethodGet, "/api/v1/users/12/posts/3", nil)
+	var response = httptest.NewRecorder()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var request = *base
+		root.ServeHTTP(response, &request)
+	}
+}
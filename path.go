@@ -0,0 +1,129 @@
+package goway
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// how router handles not canonical request paths
+// like: /users/, //users, /api/../users.
+type PathPolicy int
+
+const (
+	// serve path as is: /users/, //users and /users matches same route (default).
+	PathServe PathPolicy = iota
+
+	// redirect to cleaned path (without duplicate slashes and dots).
+	// Trailing slash kept: //users/ -> /users/.
+	PathRedirectClean
+
+	// redirect to cleaned path with trailing slash: /users -> /users/.
+	PathRedirectSlash
+
+	// redirect to cleaned path without trailing slash: /users/ -> /users.
+	//
+	// Don't use it with mounted handlers that need trailing slash
+	// (like directories of Static).
+	PathRedirectNoSlash
+
+	// trailing slash matters: /users/ and /users are different routes.
+	// Other paths served as is.
+	//
+	// In groups route path "/" means group prefix with trailing slash,
+	// and "" - group prefix without it.
+	PathStrict
+)
+
+// set path policy. Root router only, before adding groups and routes.
+//
+// Redirect status: 301 for GET and HEAD, 308 (method preserved) for others.
+// Query string preserved.
+func (r *Router) PathPolicy(policy PathPolicy) *Router {
+	r.checkFrozen()
+	if r.parent != nil {
+		panic("goway: path policy can be set only on root router")
+	}
+	if len(r.groups) > 0 || len(r.routes) > 0 {
+		panic("goway: path policy should be set before adding groups and routes")
+	}
+	r.pathPolicy = policy
+	return r
+}
+
+// is trailing slash of paths matters?
+func (r *Router) isStrictSlash() bool {
+	return r.root().pathPolicy == PathStrict
+}
+
+// get canonical request path by policy.
+//
+// isRedirect - request path not canonical.
+func (p PathPolicy) getRedirectPath(requestPath string) (canonical string, isRedirect bool) {
+	if p != PathRedirectClean && p != PathRedirectSlash && p != PathRedirectNoSlash {
+		return
+	}
+	// like OPTIONS *.
+	if !strings.HasPrefix(requestPath, "/") {
+		return
+	}
+	canonical = path.Clean(requestPath)
+	if canonical != "/" {
+		var isSlash = p == PathRedirectSlash ||
+			(p == PathRedirectClean && strings.HasSuffix(requestPath, "/"))
+		if isSlash {
+			canonical += "/"
+		}
+	}
+	return canonical, canonical != requestPath
+}
+
+// redirect request to path (with query of request).
+//
+// to - escaped path.
+func redirectToPath(response http.ResponseWriter, request *http.Request, to string) {
+	var location = to
+	if len(request.URL.RawQuery) > 0 {
+		location += "?" + request.URL.RawQuery
+	}
+	var statusCode = http.StatusPermanentRedirect
+	if request.Method == http.MethodGet || request.Method == http.MethodHead {
+		statusCode = http.StatusMovedPermanently
+	}
+	http.Redirect(response, request, location, statusCode)
+}
+
+// match routes by encoded request path pieces (root router only).
+//
+// Path split by encoded path (like /files/a%2Fb), so encoded slashes
+// stay in one piece. Then every piece decoded: variable name = a/b.
+func (r *Router) UseEncodedPath() *Router {
+	r.checkFrozen()
+	if r.parent != nil {
+		panic("goway: encoded path can be set only on root router")
+	}
+	r.useEncodedPath = true
+	return r
+}
+
+// decode escaped path pieces. Invalid pieces not changed.
+func unescapePieces(pieces []string) {
+	for i, piece := range pieces {
+		if unescaped, err := url.PathUnescape(piece); err == nil {
+			pieces[i] = unescaped
+		}
+	}
+}
+
+// is route trailing slash should be matched as empty last path piece?
+//
+// fullPathSlice - route path with prefixes of groups.
+// Root path (like /) and catch-all not counted.
+func isTrailingSlashPiece(originalPath string, fullPathSlice []string) bool {
+	if len(fullPathSlice) < 1 || !strings.HasSuffix(originalPath, "/") {
+		return false
+	}
+	var isCatchAll, _ = isCatchAllVar(fullPathSlice[len(fullPathSlice)-1])
+	return !isCatchAll
+}
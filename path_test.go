@@ -0,0 +1,205 @@
+package goway
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRouter_PathPolicy(t *testing.T) {
+	type caser struct {
+		num      int
+		policy   PathPolicy
+		method   string
+		path     string
+		status   int
+		location string
+	}
+	var cases = []caser{
+		// as is.
+		{num: 1, policy: PathServe, method: http.MethodGet, path: "//users//", status: 200},
+		{num: 2, policy: PathServe, method: http.MethodGet, path: "/users/", status: 200},
+
+		// clean.
+		{num: 3, policy: PathRedirectClean, method: http.MethodGet, path: "/users", status: 200},
+		{num: 4, policy: PathRedirectClean, method: http.MethodGet, path: "/users/", status: 200},
+		{num: 5, policy: PathRedirectClean, method: http.MethodGet, path: "//users//", status: 301, location: "/users/"},
+		{num: 6, policy: PathRedirectClean, method: http.MethodGet, path: "/api/../users?page=2", status: 301, location: "/users?page=2"},
+		{num: 7, policy: PathRedirectClean, method: http.MethodPost, path: "//users", status: 308, location: "/users"},
+
+		// add slash.
+		{num: 8, policy: PathRedirectSlash, method: http.MethodGet, path: "/users", status: 301, location: "/users/"},
+		{num: 9, policy: PathRedirectSlash, method: http.MethodGet, path: "/users/", status: 200},
+		{num: 10, policy: PathRedirectSlash, method: http.MethodGet, path: "/", status: 200},
+		{num: 11, policy: PathRedirectSlash, method: http.MethodPut, path: "/users?a=b&c=d", status: 308, location: "/users/?a=b&c=d"},
+
+		// remove slash.
+		{num: 12, policy: PathRedirectNoSlash, method: http.MethodGet, path: "/users/", status: 301, location: "/users"},
+		{num: 13, policy: PathRedirectNoSlash, method: http.MethodHead, path: "//users//", status: 301, location: "/users"},
+		{num: 14, policy: PathRedirectNoSlash, method: http.MethodDelete, path: "/users/?id=1", status: 308, location: "/users?id=1"},
+		{num: 15, policy: PathRedirectNoSlash, method: http.MethodGet, path: "/", status: 200},
+	}
+	for _, c := range cases {
+		var root = New().PathPolicy(c.policy)
+		var handler = func(w http.ResponseWriter, r *http.Request) {}
+		root.Route("/", handler)
+		root.Route("/users", handler)
+
+		var request = httptest.NewRequest(c.method, "http://example.com/", nil)
+		request.URL.Path, request.URL.RawQuery = splitQuery(c.path)
+		var recorder = httptest.NewRecorder()
+		root.ServeHTTP(recorder, request)
+		if recorder.Code != c.status {
+			t.Fatalf("case %v: expected status: %v | got: %v", c.num, c.status, recorder.Code)
+		}
+		if location := recorder.Header().Get("Location"); location != c.location {
+			t.Fatalf("case %v: expected location: %v | got: %v", c.num, c.location, location)
+		}
+	}
+}
+
+func TestRouter_PathPolicyStrict(t *testing.T) {
+	var oldHandler = Handler404
+	Handler404 = getDefaultHandler404()
+	defer func() {
+		Handler404 = oldHandler
+	}()
+	var root = New().PathPolicy(PathStrict)
+	var handler = func(response string) RouteHandler {
+		return func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, response)
+		}
+	}
+	root.Route("/", handler("root"))
+	root.Route("/users", handler("users"))
+	root.Route("/users/", handler("users/"))
+	root.Route("/files/{path...}", handler("files"))
+	var api = root.Group("/api")
+	api.Route("", handler("api"))
+	api.Route("/", handler("api/"))
+	api.Route("/posts/{id}/", handler("post/"))
+
+	type caser struct {
+		num      int
+		path     string
+		expected string
+	}
+	var cases = []caser{
+		{num: 1, path: "/", expected: "root"},
+		{num: 2, path: "/users", expected: "users"},
+		{num: 3, path: "/users/", expected: "users/"},
+		{num: 4, path: "/files/a/", expected: "files"},
+		{num: 5, path: "/api", expected: "api"},
+		{num: 6, path: "/api/", expected: "api/"},
+		{num: 7, path: "/api/posts/1/", expected: "post/"},
+		{num: 8, path: "/api/posts/1", expected: "404"},
+	}
+	for _, c := range cases {
+		var request = httptest.NewRequest(http.MethodGet, c.path, nil)
+		var recorder = httptest.NewRecorder()
+		root.ServeHTTP(recorder, request)
+		var got = recorder.Body.String()
+		if recorder.Code == 404 {
+			got = "404"
+		}
+		if got != c.expected {
+			t.Fatalf("case %v: expected: %v | got: %v", c.num, c.expected, got)
+		}
+	}
+
+	// URL building keeps slash.
+	root.Route("/posts/{id}/", handler("post")).Name("post")
+	var built, err = root.URL("post", "id", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if built.Path != "/posts/1/" {
+		t.Fatalf("expected: /posts/1/ | got: %v", built.Path)
+	}
+}
+
+func TestRouter_PathPolicyPanics(t *testing.T) {
+	var isPanics = func(fn func()) (isPanic bool) {
+		defer func() {
+			isPanic = recover() != nil
+		}()
+		fn()
+		return
+	}
+	if !isPanics(func() { New().Group("/api").PathPolicy(PathStrict) }) {
+		t.Fatalf("expected panic on group")
+	}
+	var root = New()
+	root.Route("/", nil)
+	if !isPanics(func() { root.PathPolicy(PathStrict) }) {
+		t.Fatalf("expected panic after routes added")
+	}
+}
+
+// split path like /users?page=2 to path and query.
+func splitQuery(pathWithQuery string) (path string, query string) {
+	var index = strings.Index(pathWithQuery, "?")
+	if index < 0 {
+		return pathWithQuery, ""
+	}
+	return pathWithQuery[:index], pathWithQuery[index+1:]
+}
+
+func TestRouter_UseEncodedPath(t *testing.T) {
+	var oldHandler = Handler404
+	Handler404 = getDefaultHandler404()
+	defer func() {
+		Handler404 = oldHandler
+	}()
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, Vars(r))
+	}
+	type caser struct {
+		num       int
+		isEncoded bool
+		path      string
+		expected  string
+	}
+	var cases = []caser{
+		{num: 1, isEncoded: false, path: "/files/a%2Fb", expected: "404"},
+		{num: 2, isEncoded: true, path: "/files/a%2Fb", expected: "map[name:a/b]"},
+		{num: 3, isEncoded: true, path: "/files/key%3Fv%3D1", expected: "map[name:key?v=1]"},
+		{num: 4, isEncoded: true, path: "/files/a%20b/meta", expected: "map[meta:a b]"},
+		{num: 5, isEncoded: true, path: "/static%20dir/x", expected: "map[]"},
+		{num: 6, isEncoded: true, path: "/files/a%2Fb/c", expected: "404"},
+	}
+	for _, c := range cases {
+		var root = New()
+		if c.isEncoded {
+			root.UseEncodedPath()
+		}
+		root.Route("/files/{name}", handler)
+		root.Route("/files/{meta}/meta", handler)
+		root.Route("/static dir/x", handler)
+
+		var request = httptest.NewRequest(http.MethodGet, c.path, nil)
+		var recorder = httptest.NewRecorder()
+		root.ServeHTTP(recorder, request)
+		var got = recorder.Body.String()
+		if recorder.Code == 404 {
+			got = "404"
+		}
+		if got != c.expected {
+			t.Fatalf("case %v: expected: %v | got: %v", c.num, c.expected, got)
+		}
+	}
+}
+
+func TestRouter_UseEncodedPathRedirect(t *testing.T) {
+	var root = New().PathPolicy(PathRedirectClean).UseEncodedPath()
+	root.Route("/files/{name}", func(w http.ResponseWriter, r *http.Request) {})
+	var request = httptest.NewRequest(http.MethodGet, "//files/a%2Fb?x=1", nil)
+	var recorder = httptest.NewRecorder()
+	root.ServeHTTP(recorder, request)
+	var expected = "/files/a%2Fb?x=1"
+	if location := recorder.Header().Get("Location"); location != expected {
+		t.Fatalf("expected location: %v | got: %v", expected, location)
+	}
+}
@@ -0,0 +1,190 @@
+package goway
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"runtime/debug"
+)
+
+// gets panic from middleware or route endpoint (see Router.Recover).
+type PanicReporter func(request *http.Request, recovered interface{}, stack []byte)
+
+// when panic recovered.
+//
+// Used by routers without own reporter (see Router.Recover).
+var ReportPanic = getDefaultPanicReporter()
+
+// recovered panic. Sent to error handler as cause of 500 HTTPError.
+type PanicError struct {
+	// value passed to panic().
+	Value interface{}
+
+	// goroutine stack trace.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// recover panics from middlewares and route endpoints. Root routers only.
+//
+// Panic reported by reporter (nil - ReportPanic), then
+// if response headers not sent - error handler (see Router.ErrorHandler)
+// gets 500 HTTPError with *PanicError cause.
+// Otherwise response aborted (like http.ErrAbortHandler).
+//
+// Error handler of matched route used for panics in route endpoint
+// and route middlewares, error handler of matched group - for group middlewares.
+//
+// http.ErrAbortHandler not recovered and not reported.
+func (r *Router) Recover(reporter PanicReporter) *Router {
+	r.checkFrozen()
+	if r.parent != nil {
+		panic("goway: recovery can be set only on root router")
+	}
+	r.isRecover = true
+	r.panicReporter = reporter
+	return r
+}
+
+// get panic reporter of router (or ReportPanic).
+func (r *Router) getPanicReporter() PanicReporter {
+	if r.panicReporter != nil {
+		return r.panicReporter
+	}
+	return ReportPanic
+}
+
+// recovery state of request (see getRecoverWriter).
+const ctxRecoverName CTX_VAL = "GOWAY_ROUTER_RECOVER"
+
+// serve request and recover panic.
+func (r *Router) serveRecover(response http.ResponseWriter, request *http.Request) {
+	var writer = &recoverResponseWriter{ResponseWriter: response}
+	request = request.WithContext(context.WithValue(request.Context(), ctxRecoverName, writer))
+	defer func() {
+		var recovered = recover()
+		if recovered == nil {
+			return
+		}
+		if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+			// abort response without logging (same as net/http).
+			panic(recovered)
+		}
+		var stack = debug.Stack()
+		r.getPanicReporter()(request, recovered, stack)
+		if writer.isHeaderWritten {
+			// part of response sent, client should not get it as complete.
+			panic(http.ErrAbortHandler)
+		}
+		// headers of failed response (like Content-Encoding) not for error.
+		var header = response.Header()
+		for key := range header {
+			delete(header, key)
+		}
+		var err = &HTTPError{
+			Status: http.StatusInternalServerError,
+			Cause:  &PanicError{Value: recovered, Stack: stack},
+		}
+		writer.getErrorHandler(r)(response, request, err)
+	}()
+	r.serveEndpoint(writer, request)
+}
+
+// remember matched group / route (if recovery enabled),
+// so its error handler used for panics.
+func (r *Router) setServing(request *http.Request, group *Router, route *Route) {
+	if !r.root().isRecover {
+		return
+	}
+	var writer = getRecoverWriter(request)
+	if writer == nil {
+		return
+	}
+	if group != nil {
+		writer.group = group
+	}
+	if route != nil {
+		writer.route = route
+	}
+}
+
+// get recovery state of request (nil if recovery disabled).
+func getRecoverWriter(request *http.Request) *recoverResponseWriter {
+	var writer, _ = request.Context().Value(ctxRecoverName).(*recoverResponseWriter)
+	return writer
+}
+
+// response writer that knows if headers sent.
+type recoverResponseWriter struct {
+	http.ResponseWriter
+
+	// is headers sent (or connection hijacked)?
+	isHeaderWritten bool
+
+	// last matched group (nil if not matched).
+	group *Router
+
+	// matched route (nil if not matched).
+	route *Route
+}
+
+// get error handler of matched route / group (or root).
+func (w *recoverResponseWriter) getErrorHandler(root *Router) ErrorHandler {
+	if w.route != nil {
+		return w.route.getErrorHandler()
+	}
+	if w.group != nil {
+		return w.group.getErrorHandler()
+	}
+	return root.getErrorHandler()
+}
+
+func (w *recoverResponseWriter) WriteHeader(status int) {
+	// 1xx informational responses not final.
+	if status >= 200 {
+		w.isHeaderWritten = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recoverResponseWriter) Write(data []byte) (int, error) {
+	w.isHeaderWritten = true
+	return w.ResponseWriter.Write(data)
+}
+
+// flush (if supported by original writer).
+func (w *recoverResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.isHeaderWritten = true
+		flusher.Flush()
+	}
+}
+
+// hijack connection (if supported by original writer).
+func (w *recoverResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	var hijacker, ok = w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("goway: response writer not supports hijacking")
+	}
+	w.isHeaderWritten = true
+	return hijacker.Hijack()
+}
+
+// get original writer (for http.ResponseController).
+func (w *recoverResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// default panic reporter.
+func getDefaultPanicReporter() PanicReporter {
+	return func(request *http.Request, recovered interface{}, stack []byte) {
+		log.Printf("goway: panic serving %v %v: %v\n%s", request.Method, request.URL.Path, recovered, stack)
+	}
+}
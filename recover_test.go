@@ -0,0 +1,146 @@
+package goway
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRouter_Recover(t *testing.T) {
+	var reported []interface{}
+	var stacks []string
+	var reporter = func(r *http.Request, recovered interface{}, stack []byte) {
+		reported = append(reported, recovered)
+		stacks = append(stacks, string(stack))
+	}
+	var panicking = func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}
+	var root = New().Recover(reporter)
+	root.Route("/panic", panicking)
+	root.Route("/headers", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("ETag", `"abc"`)
+		panic("headers")
+	})
+	root.Route("/written", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "partial")
+		panic("late")
+	})
+	root.Route("/abort", func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+	var api = root.Group("/api").ErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		var panicErr *PanicError
+		if errors.As(err, &panicErr) {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, "api: ", panicErr.Value)
+		}
+	})
+	api.Route("/panic", panicking)
+	api.Route("/middleware", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}).Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("middleware")
+		})
+	})
+	var group = api.Group("/group").Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("group middleware")
+		})
+	})
+	group.Route("/route", panicking)
+	// user middleware gets original panic value.
+	root.Route("/user-recover", func(w http.ResponseWriter, r *http.Request) {
+		panic(&VarError{Name: "id", Err: ErrVarInvalid})
+	}).Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				var err, _ = recover().(error)
+				var varErr *VarError
+				if errors.As(err, &varErr) {
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprint(w, "user: ", varErr.Name)
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	})
+
+	type caser struct {
+		num       int
+		path      string
+		status    int
+		expected  string
+		reported  interface{}
+		isAborted bool
+	}
+	var cases = []caser{
+		{num: 1, path: "/panic", status: 500, expected: "Internal Server Error\n", reported: "boom"},
+		// group error handler.
+		{num: 2, path: "/api/panic", status: 500, expected: "api: boom", reported: "boom"},
+		{num: 3, path: "/api/middleware", status: 500, expected: "api: middleware", reported: "middleware"},
+		// group middleware: error handler of parent group.
+		{num: 4, path: "/api/group/route", status: 500, expected: "api: group middleware", reported: "group middleware"},
+		{num: 5, path: "/user-recover", status: 400, expected: "user: id"},
+		// headers set before panic not sent.
+		{num: 6, path: "/headers", status: 500, expected: "Internal Server Error\n", reported: "headers"},
+		// response started: reported and aborted.
+		{num: 7, path: "/written", reported: "late", isAborted: true},
+		// not reported.
+		{num: 8, path: "/abort", isAborted: true},
+	}
+	for _, c := range cases {
+		reported = nil
+		stacks = nil
+		var recorder = httptest.NewRecorder()
+		var recovered = func() (recovered interface{}) {
+			defer func() {
+				recovered = recover()
+			}()
+			root.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, c.path, nil))
+			return
+		}()
+		if c.isAborted {
+			if recovered != http.ErrAbortHandler {
+				t.Fatalf("case num: %v | expected abort | got: %v", c.num, recovered)
+			}
+		} else if recovered != nil {
+			t.Fatalf("case num: %v | unexpected panic: %v", c.num, recovered)
+		} else if recorder.Code != c.status || recorder.Body.String() != c.expected {
+			t.Fatalf("case num: %v | expected: %v %q | got: %v %q", c.num, c.status, c.expected, recorder.Code, recorder.Body.String())
+		}
+		if len(recorder.Header().Get("Content-Encoding")) > 0 || len(recorder.Header().Get("ETag")) > 0 {
+			t.Fatalf("case num: %v | unexpected headers: %v", c.num, recorder.Header())
+		}
+		if c.reported == nil {
+			if len(reported) > 0 {
+				t.Fatalf("case num: %v | unexpected report: %v", c.num, reported)
+			}
+			continue
+		}
+		if len(reported) != 1 || reported[0] != c.reported {
+			t.Fatalf("case num: %v | expected report: %v | got: %v", c.num, c.reported, reported)
+		}
+		if !strings.Contains(stacks[0], "TestRouter_Recover") {
+			t.Fatalf("case num: %v | expected stack of handler | got: %v", c.num, stacks[0])
+		}
+	}
+}
+
+func TestRouter_RecoverDisabled(t *testing.T) {
+	var root = New()
+	root.Route("/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	defer func() {
+		if recovered := recover(); recovered != "boom" {
+			t.Fatalf("expected panic | got: %v", recovered)
+		}
+	}()
+	root.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+}
@@ -5,8 +5,20 @@ import (
 )
 
 type Route struct {
-	// is route under group?
-	isUnderGroup bool
+	// router that owns route.
+	router *Router
+
+	// tree node with route.
+	node *node
+
+	// when endpoint returns error (nil - same as router).
+	errorHandler ErrorHandler
+
+	// route name (for URL building).
+	name string
+
+	// is route mounted handler (matches all paths under route path)?
+	isMount bool
 
 	// prefix tools.
 	prefix prefixes
@@ -14,42 +26,85 @@ type Route struct {
 	// allowed route methods.
 	allowedMethods []string
 
+	// request matchers (host, headers, queries, etc).
+	matchers []requestMatcher
+
 	// route middleware chain.
 	middleware MiddlewareFunc
 
+	// how much middlewares in chain.
+	middlewareCount int
+
 	// route endpoint.
-	handler RouteHandler
+	handler http.Handler
+
+	// middleware chain wrapped around route endpoint.
+	wrapped http.Handler
+
+	// answer OPTIONS requests automatically (nil - same as router).
+	autoOptions *bool
+
+	// CORS policy (nil - same as router).
+	cors *CORS
 }
 
-func (r *Route) new(excludeCount int, to string, handler RouteHandler) {
-	if excludeCount > 0 {
-		r.isUnderGroup = true
-		r.prefix.excludeCount = excludeCount
-	}
+func (r *Route) new(excludeCount int, to string, handler http.Handler) {
+	r.prefix.excludeCount = excludeCount
 	r.prefix.setPath(to)
 	r.prefix.setPathSlice()
 	r.handler = handler
 }
 
 func (r *Route) ServeHTTP(response http.ResponseWriter, request *http.Request) {
-	// run middleware.
-	var isResponseSended = executeMiddleware(response, request, r.middleware)
-	if isResponseSended {
+	// run middleware (if exists). Middleware calls endpoint by itself.
+	if r.wrapped != nil {
+		r.wrapped.ServeHTTP(response, request)
 		return
 	}
-
-	r.handler(response, request)
-	return
+	r.handler.ServeHTTP(response, request)
 }
 
 // route trigger on this methods only.
 func (r *Route) Methods(methods ...string) *Route {
+	r.checkFrozen()
 	r.allowedMethods = processAllowedMethods(r.allowedMethods, methods...)
+	if r.node != nil {
+		r.node.indexMethods()
+	}
 	return r
 }
 
 // provide middleware.
 func (r *Route) Use(middleware ...MiddlewareFunc) *Route {
+	r.checkFrozen()
 	r.middleware = processMiddleware(r.middleware, middleware...)
+	r.middlewareCount += countMiddleware(middleware...)
+	r.wrapped = wrapMiddleware(r.middleware, r.handler)
+	return r
+}
+
+// set route name. Name used for URL building (see Router.URL).
+//
+// Names should be unique across all groups of root router.
+func (r *Route) Name(name string) *Route {
+	r.checkFrozen()
+	if r.router != nil {
+		r.router.root().addName(name, r)
+	}
+	r.name = name
+	return r
+}
+
+// answer OPTIONS requests automatically (overrides router setting).
+func (r *Route) AutoOptions(enabled bool) *Route {
+	r.checkFrozen()
+	r.autoOptions = &enabled
+	return r
+}
+
+// set CORS policy (overrides router policy).
+func (r *Route) CORS(policy *CORS) *Route {
+	r.checkFrozen()
+	r.cors = policy
 	return r
 }
@@ -1,6 +1,7 @@
 package goway
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -255,6 +256,9 @@ func TestRouting_GroupAllowedMethods(t *testing.T) {
 
 	// Not allowed method.
 	var notAllowedExpected = "method not allowed"
+	defer func(old RouteHandler) {
+		Handler405 = old
+	}(Handler405)
 	Handler405 = func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(notAllowedExpected))
 	}
@@ -313,6 +317,9 @@ func TestRouting_EmptyRoutesPathInGroup(t *testing.T) {
 
 	// Not allowed method.
 	var notAllowedExpected = "method not allowed"
+	defer func(old RouteHandler) {
+		Handler405 = old
+	}(Handler405)
 	Handler405 = func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(notAllowedExpected))
 	}
@@ -365,6 +372,9 @@ func TestRouting_Router404(t *testing.T) {
 	var expectedResponse = "404 handler"
 	//
 
+	defer func(old RouteHandler) {
+		Handler404 = old
+	}(Handler404)
 	Handler404 = func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(expectedResponse))
 	}
@@ -392,6 +402,9 @@ func TestRouting_Group404(t *testing.T) {
 	var expectedResponse = "404 handler"
 	//
 
+	defer func(old RouteHandler) {
+		Handler404 = old
+	}(Handler404)
 	Handler404 = func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(expectedResponse))
 	}
@@ -447,6 +460,9 @@ func TestRouting_GroupNoMatch(t *testing.T) {
 
 	// 404.
 	var is404Executed = false
+	defer func(old RouteHandler) {
+		Handler404 = old
+	}(Handler404)
 	Handler404 = func(w http.ResponseWriter, r *http.Request) {
 		is404Executed = true
 	}
@@ -465,6 +481,56 @@ func TestRouting_GroupNoMatch(t *testing.T) {
 
 }
 
+func TestRouting_EmptyPrefixGroup(t *testing.T) {
+	var root = New()
+	var handler = func(name string) RouteHandler {
+		return func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, name)
+		}
+	}
+	var isMiddlewareExecuted = false
+	var admin = root.Group("").Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			isMiddlewareExecuted = true
+			next.ServeHTTP(w, r)
+		})
+	}).NotFound(handler("admin 404"))
+	admin.Route("/admin", handler("admin")).Methods(http.MethodPost)
+	admin.Group("").Route("/admin/users/{id}", handler("user"))
+	root.Route("/x", handler("x"))
+	root.Route("/{page}", handler("page"))
+	root.NotFound(handler("root 404"))
+
+	type caser struct {
+		num          int
+		method       string
+		path         string
+		status       int
+		expected     string
+		isMiddleware bool
+	}
+	var cases = []caser{
+		{num: 1, method: http.MethodGet, path: "/x", status: 200, expected: "x"},
+		{num: 2, method: http.MethodGet, path: "/about", status: 200, expected: "page"},
+		{num: 3, method: http.MethodPost, path: "/admin", status: 200, expected: "admin", isMiddleware: true},
+		// route path matched: group claims path.
+		{num: 4, method: http.MethodGet, path: "/admin", status: 405, expected: "method not allowed", isMiddleware: true},
+		{num: 5, method: http.MethodGet, path: "/admin/users/12", status: 200, expected: "user", isMiddleware: true},
+		{num: 6, method: http.MethodGet, path: "/x/y", status: 200, expected: "root 404"},
+	}
+	for _, c := range cases {
+		isMiddlewareExecuted = false
+		var recorder = httptest.NewRecorder()
+		root.ServeHTTP(recorder, httptest.NewRequest(c.method, c.path, nil))
+		if recorder.Code != c.status || recorder.Body.String() != c.expected {
+			t.Fatalf("case num: %v | expected: %v %q | got: %v %q", c.num, c.status, c.expected, recorder.Code, recorder.Body.String())
+		}
+		if isMiddlewareExecuted != c.isMiddleware {
+			t.Fatalf("case num: %v | expected group middleware executed: %v", c.num, c.isMiddleware)
+		}
+	}
+}
+
 func TestRouting_RouteNoMatch(t *testing.T) {
 	var root = New()
 
@@ -505,6 +571,9 @@ func TestRouting_RouteNoMatch(t *testing.T) {
 
 	// 404.
 	var is404Executed = false
+	defer func(old RouteHandler) {
+		Handler404 = old
+	}(Handler404)
 	Handler404 = func(w http.ResponseWriter, r *http.Request) {
 		is404Executed = true
 	}
@@ -654,6 +723,245 @@ func TestRouting_LongLittlePaths(t *testing.T) {
 	}
 }
 
+func TestRouting_CatchAll(t *testing.T) {
+	var root = New()
+
+	// init server.
+	var requestor = Requestor{}
+	requestor.New(root)
+	defer requestor.Server.Close()
+
+	var group = root.Group("/files")
+	group.Route("/{path...}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "catch-all: "+Vars(r)["path"])
+	})
+	group.Route("/{name}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "var: "+Vars(r)["name"])
+	})
+	group.Route("/readme", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "static")
+	})
+
+	var cases = map[string]string{
+		"/files/readme":          "static",
+		"/files/photo.png":       "var: photo.png",
+		"/files/users/me/a.png":  "catch-all: users/me/a.png",
+		"/files/readme/a/b/c.md": "catch-all: readme/a/b/c.md",
+	}
+	for requestPath, expectedResponse := range cases {
+		var body, err = requestor.PrettySender(http.MethodGet, requestPath, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body != expectedResponse {
+			t.Fatalf("expected body: %v, got: %v", expectedResponse, body)
+		}
+	}
+}
+
+func TestRouting_AllowHeader(t *testing.T) {
+	var root = New()
+
+	// init server.
+	var requestor = Requestor{}
+	requestor.New(root)
+	defer requestor.Server.Close()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {}
+	root.Route("/users/{id}", handler).Methods(http.MethodGet, http.MethodPut)
+	root.Route("/users/{id}", handler).Methods(http.MethodDelete, http.MethodGet)
+	root.Route("/users/me", handler).Methods(http.MethodPatch)
+	root.Group("/api").Methods(http.MethodGet)
+
+	type caser struct {
+		num      int
+		path     string
+		expected string
+	}
+	var cases = []caser{
+		{num: 1, path: "/users/12", expected: "GET, PUT, DELETE, HEAD"},
+		// GET /users/me served by /users/{id}.
+		{num: 2, path: "/users/me", expected: "PATCH, GET, PUT, DELETE, HEAD"},
+		{num: 3, path: "/api/users", expected: "GET, HEAD"},
+	}
+	for _, cased := range cases {
+		var res, err = requestor.POST(cased.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		var allow = res.Header.Get("Allow")
+		if allow != cased.expected {
+			t.Fatalf("case num: %v | expected: %v | got: %v", cased.num, cased.expected, allow)
+		}
+	}
+
+	// allowed methods available in 405 handler.
+	var req = httptest.NewRequest(http.MethodPost, "/users/12", nil)
+	var rec = httptest.NewRecorder()
+	var allowed []string
+	var oldHandler = Handler405
+	Handler405 = func(w http.ResponseWriter, r *http.Request) {
+		allowed = AllowedMethods(r)
+	}
+	defer func() {
+		Handler405 = oldHandler
+	}()
+	root.ServeHTTP(rec, req)
+	var expectedAllowed = []string{http.MethodGet, http.MethodPut, http.MethodDelete, http.MethodHead}
+	if !reflect.DeepEqual(allowed, expectedAllowed) {
+		t.Fatalf("expected: %v | got: %v", expectedAllowed, allowed)
+	}
+}
+
+func TestRouting_ImplicitHead(t *testing.T) {
+	var root = New()
+
+	// init server.
+	var requestor = Requestor{}
+	requestor.New(root)
+	defer requestor.Server.Close()
+
+	var body = strings.Repeat("a", 10000)
+	var isGetExecuted = false
+	root.Route("/big", func(w http.ResponseWriter, r *http.Request) {
+		isGetExecuted = true
+		w.Header().Set("X-Handler", "GET")
+		w.Write([]byte(body))
+	}).Methods(http.MethodGet)
+
+	root.Route("/explicit", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Handler", "GET")
+	}).Methods(http.MethodGet)
+	root.Route("/explicit", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Handler", "HEAD")
+	}).Methods(http.MethodHead)
+
+	// GET handler.
+	var res, err = requestor.HEAD("/big")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if !isGetExecuted || res.StatusCode != http.StatusOK {
+		t.Fatalf("GET handler should serve HEAD request")
+	}
+	if res.ContentLength != int64(len(body)) {
+		t.Fatalf("expected Content-Length: %v | got: %v", len(body), res.ContentLength)
+	}
+
+	// HEAD handler.
+	res, err = requestor.HEAD("/explicit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if handler := res.Header.Get("X-Handler"); handler != "HEAD" {
+		t.Fatalf("expected HEAD handler, got: %v", handler)
+	}
+}
+
+func TestRouting_RouterErrorHandlers(t *testing.T) {
+	var newHandler = func(body string) RouteHandler {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(body))
+		}
+	}
+	var root = New().NotFound(newHandler("html 404")).MethodNotAllowed(newHandler("html 405"))
+	root.Route("/about", newHandler("about")).Methods(http.MethodGet)
+	var api = root.Group("/api").NotFound(newHandler("json 404")).MethodNotAllowed(newHandler("json 405"))
+	api.Route("/users", newHandler("users")).Methods(http.MethodGet)
+	var admin = api.Group("/admin").Methods(http.MethodGet)
+	admin.Route("/stats", newHandler("stats"))
+
+	// another router with own handlers.
+	var another = New().NotFound(newHandler("another 404"))
+
+	type caser struct {
+		num      int
+		router   *Router
+		method   string
+		path     string
+		expected string
+	}
+	var cases = []caser{
+		{num: 1, router: root, method: http.MethodGet, path: "/other", expected: "html 404"},
+		{num: 2, router: root, method: http.MethodPost, path: "/about", expected: "html 405"},
+		{num: 3, router: root, method: http.MethodGet, path: "/api/other", expected: "json 404"},
+		{num: 4, router: root, method: http.MethodPost, path: "/api/users", expected: "json 405"},
+		{num: 5, router: root, method: http.MethodGet, path: "/api/admin/other", expected: "json 404"},
+		{num: 6, router: root, method: http.MethodPost, path: "/api/admin/stats", expected: "json 405"},
+		{num: 7, router: another, method: http.MethodGet, path: "/other", expected: "another 404"},
+	}
+	for _, cased := range cases {
+		var req = httptest.NewRequest(cased.method, cased.path, nil)
+		var rec = httptest.NewRecorder()
+		cased.router.ServeHTTP(rec, req)
+		if rec.Body.String() != cased.expected {
+			t.Fatalf("case num: %v | expected body: %v | got: %v", cased.num, cased.expected, rec.Body.String())
+		}
+	}
+}
+
+type testCtxKey string
+
+// response writer that adds prefix to body.
+type prefixWriter struct {
+	http.ResponseWriter
+	prefix string
+}
+
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	return p.ResponseWriter.Write(append([]byte(p.prefix), data...))
+}
+
+func TestRouting_MiddlewarePropagation(t *testing.T) {
+	var withValue = func(key string, value string) MiddlewareFunc {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+				var ctx = context.WithValue(request.Context(), testCtxKey(key), value)
+				next.ServeHTTP(response, request.WithContext(ctx))
+			})
+		}
+	}
+	var withWriter = func(prefix string) MiddlewareFunc {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+				next.ServeHTTP(&prefixWriter{ResponseWriter: response, prefix: prefix}, request)
+			})
+		}
+	}
+
+	var root = New()
+	root.Use(withValue("root", "1"), withWriter("root:"))
+	var group = root.Group("/api/{version}").Use(withValue("group", "2"))
+
+	// init server.
+	var requestor = Requestor{}
+	requestor.New(root)
+	defer requestor.Server.Close()
+
+	group.Route("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var ctx = r.Context()
+		fmt.Fprintf(w, "%v %v %v %v %v",
+			ctx.Value(testCtxKey("root")),
+			ctx.Value(testCtxKey("group")),
+			ctx.Value(testCtxKey("route")),
+			Vars(r)["version"],
+			Vars(r)["id"],
+		)
+	}).Use(withValue("route", "3"), withWriter("route:"))
+
+	var expectedResponse = "root:route:1 2 3 v1 12"
+	var body, err = requestor.PrettySender(http.MethodGet, "/api/v1/users/12", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != expectedResponse {
+		t.Fatalf("expected body: %v, got: %v", expectedResponse, body)
+	}
+}
+
 ////////////////////////////
 type Requestor struct {
 	Server *httptest.Server
@@ -0,0 +1,290 @@
+package goway
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// file names with hash like: main.3f2a1b9c.js or main-3f2a1b9c.js.
+var hashedFilePattern = regexp.MustCompile(`[.-][0-9a-fA-F]{8,}\.[^.]+$`)
+
+// static files options.
+type StaticOptions struct {
+	// index files for directories. Default: index.html.
+	IndexFiles []string
+
+	// show directory listing if directory has no index file.
+	Listing bool
+
+	// serve root index file (like index.html) when file not found
+	// and path has no extension. For single page applications.
+	SPA bool
+
+	// serve precompressed .br / .gz files if client accepts it.
+	Precompressed bool
+
+	// files with name matching pattern cached forever
+	// (Cache-Control: public, max-age=31536000, immutable).
+	//
+	// Default: names with hash like main.3f2a1b9c.js.
+	ImmutablePattern *regexp.Regexp
+
+	// Cache-Control header for other files. Default: not set.
+	CacheControl string
+}
+
+// serve files from fsys (like embed.FS or os.DirFS) under prefix.
+//
+// Only GET and HEAD requests allowed.
+func (r *Router) Static(prefix string, fsys fs.FS, opts *StaticOptions) *Route {
+	if opts == nil {
+		opts = &StaticOptions{}
+	}
+	var handler = &staticHandler{
+		router: r,
+		fsys:   fsys,
+		opts:   *opts,
+	}
+	if len(handler.opts.IndexFiles) < 1 {
+		handler.opts.IndexFiles = []string{"index.html"}
+	}
+	if handler.opts.ImmutablePattern == nil {
+		handler.opts.ImmutablePattern = hashedFilePattern
+	}
+	return r.Mount(prefix, handler).Methods(http.MethodGet)
+}
+
+// precompressed file extensions by encoding (in priority order).
+var precompressed = []struct {
+	encoding  string
+	extension string
+}{
+	{encoding: "br", extension: ".br"},
+	{encoding: "gzip", extension: ".gz"},
+}
+
+// serves static files.
+type staticHandler struct {
+	// router for 404 handler.
+	router *Router
+
+	fsys fs.FS
+
+	opts StaticOptions
+
+	// ETags of files without modification time (like in embed.FS).
+	etags sync.Map
+}
+
+func (s *staticHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	// path already cleaned, but check it again.
+	var name = strings.TrimPrefix(path.Clean("/"+request.URL.Path), "/")
+	if len(name) < 1 {
+		name = "."
+	}
+	if !fs.ValidPath(name) || strings.Contains(name, "\\") {
+		s.notFound(response, request)
+		return
+	}
+
+	var info, err = fs.Stat(s.fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		if s.opts.SPA && len(path.Ext(name)) < 1 {
+			s.serveFile(response, request, s.opts.IndexFiles[0])
+			return
+		}
+		s.notFound(response, request)
+		return
+	}
+	if err != nil {
+		http.Error(response, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if !info.IsDir() {
+		s.serveFile(response, request, name)
+		return
+	}
+
+	// directory: redirect to path with slash (for relative links).
+	if !strings.HasSuffix(request.URL.Path, "/") {
+		// relative, because request path without prefix.
+		// escaped: directory name can contain ?, # or % (and : - String adds ./).
+		var redirectTo = (&url.URL{Path: path.Base(request.URL.Path) + "/"}).String()
+		if len(request.URL.RawQuery) > 0 {
+			redirectTo += "?" + request.URL.RawQuery
+		}
+		response.Header().Set("Location", redirectTo)
+		response.WriteHeader(http.StatusMovedPermanently)
+		return
+	}
+	for _, index := range s.opts.IndexFiles {
+		var indexName = path.Join(name, index)
+		if indexInfo, err := fs.Stat(s.fsys, indexName); err == nil && !indexInfo.IsDir() {
+			s.serveFile(response, request, indexName)
+			return
+		}
+	}
+	if s.opts.Listing {
+		s.serveListing(response, request, name)
+		return
+	}
+	s.notFound(response, request)
+}
+
+// send 404 by router handler.
+func (s *staticHandler) notFound(response http.ResponseWriter, request *http.Request) {
+	s.router.getHandler404()(response, request)
+}
+
+// serve file (or its precompressed version).
+func (s *staticHandler) serveFile(response http.ResponseWriter, request *http.Request, name string) {
+	var header = response.Header()
+	var servedName = name
+	var encoding = ""
+	if s.opts.Precompressed {
+		header.Add("Vary", "Accept-Encoding")
+		for _, variant := range precompressed {
+			if !isEncodingAccepted(request, variant.encoding) {
+				continue
+			}
+			if info, err := fs.Stat(s.fsys, name+variant.extension); err == nil && !info.IsDir() {
+				servedName = name + variant.extension
+				encoding = variant.encoding
+				break
+			}
+		}
+	}
+
+	var file, err = s.fsys.Open(servedName)
+	if err != nil {
+		s.notFound(response, request)
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		http.Error(response, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// content.
+	var content io.ReadSeeker
+	if seeker, ok := file.(io.ReadSeeker); ok {
+		content = seeker
+	} else {
+		var data, err = io.ReadAll(file)
+		if err != nil {
+			http.Error(response, "internal server error", http.StatusInternalServerError)
+			return
+		}
+		content = bytes.NewReader(data)
+	}
+
+	// headers.
+	if contentType := mime.TypeByExtension(path.Ext(name)); len(contentType) > 0 {
+		header.Set("Content-Type", contentType)
+	}
+	etag, err := s.getETag(servedName, info, content)
+	if err != nil {
+		http.Error(response, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	header.Set("ETag", etag)
+	// set after all checks: error responses not encoded.
+	if len(encoding) > 0 {
+		header.Set("Content-Encoding", encoding)
+	}
+	if s.opts.ImmutablePattern.MatchString(path.Base(name)) {
+		header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else if len(s.opts.CacheControl) > 0 {
+		header.Set("Cache-Control", s.opts.CacheControl)
+	}
+
+	// ServeContent handles Range, If-None-Match, If-Modified-Since.
+	http.ServeContent(response, request, name, info.ModTime(), content)
+}
+
+// get file ETag.
+//
+// If file has modification time - by size and time,
+// otherwise by content hash (cached).
+func (s *staticHandler) getETag(name string, info fs.FileInfo, content io.ReadSeeker) (string, error) {
+	if !info.ModTime().IsZero() {
+		return fmt.Sprintf(`"%x-%x"`, info.Size(), info.ModTime().UnixNano()), nil
+	}
+	if cached, ok := s.etags.Load(name); ok {
+		return cached.(string), nil
+	}
+	var hash = sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	var etag = `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+	s.etags.Store(name, etag)
+	return etag, nil
+}
+
+// serve directory listing.
+func (s *staticHandler) serveListing(response http.ResponseWriter, request *http.Request, name string) {
+	var entries, err = fs.ReadDir(s.fsys, name)
+	if err != nil {
+		http.Error(response, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	response.Header().Set("Content-Type", "text/html; charset=utf-8")
+	var listing strings.Builder
+	listing.WriteString("<!doctype html>\n<meta name=\"viewport\" content=\"width=device-width\">\n<pre>\n")
+	for _, entry := range entries {
+		var entryName = entry.Name()
+		if entry.IsDir() {
+			entryName += "/"
+		}
+		var link = url.URL{Path: entryName}
+		fmt.Fprintf(&listing, "<a href=\"%s\">%s</a>\n", link.String(), html.EscapeString(entryName))
+	}
+	listing.WriteString("</pre>\n")
+	response.Header().Set("Content-Length", strconv.Itoa(listing.Len()))
+	io.WriteString(response, listing.String())
+}
+
+// is encoding in Accept-Encoding header (and q not 0)?
+func isEncodingAccepted(request *http.Request, encoding string) bool {
+	for _, header := range request.Header.Values("Accept-Encoding") {
+		for _, part := range strings.Split(header, ",") {
+			var params = strings.Split(part, ";")
+			if !strings.EqualFold(strings.TrimSpace(params[0]), encoding) {
+				continue
+			}
+			for _, param := range params[1:] {
+				param = strings.TrimSpace(param)
+				if !strings.HasPrefix(param, "q=") {
+					continue
+				}
+				var quality, err = strconv.ParseFloat(param[2:], 64)
+				if err == nil && quality <= 0 {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
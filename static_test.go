@@ -0,0 +1,128 @@
+package goway
+
+import (
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRouting_Static(t *testing.T) {
+	var fsys = fstest.MapFS{
+		"index.html":            {Data: []byte("index")},
+		"app.3f2a1b9c.js":       {Data: []byte("hashed js")},
+		"app.3f2a1b9c.js.br":    {Data: []byte("br js")},
+		"app.3f2a1b9c.js.gz":    {Data: []byte("gzip js")},
+		"docs/readme.txt":       {Data: []byte("readme")},
+		"empty/file.txt":        {Data: []byte("file")},
+		"empty/nested/file.txt": {Data: []byte("nested")},
+		"odd ?#%/file.txt":      {Data: []byte("odd")},
+	}
+	var root = New().NotFound(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+	})
+	root.Static("/static", fsys, &StaticOptions{Precompressed: true, CacheControl: "no-cache"})
+	root.Static("/listing", fsys, &StaticOptions{Listing: true})
+	root.Static("/app", fsys, &StaticOptions{SPA: true})
+
+	type caser struct {
+		num      int
+		path     string
+		headers  map[string]string
+		status   int
+		expected string
+		response map[string]string
+	}
+	var cases = []caser{
+		{num: 1, path: "/static/", status: 200, expected: "index", response: map[string]string{"Cache-Control": "no-cache", "Content-Type": "text/html; charset=utf-8"}},
+		{num: 2, path: "/static/docs/readme.txt", status: 200, expected: "readme"},
+		{num: 3, path: "/static/app.3f2a1b9c.js", status: 200, expected: "hashed js", response: map[string]string{"Cache-Control": "public, max-age=31536000, immutable"}},
+		{num: 4, path: "/static/app.3f2a1b9c.js", headers: map[string]string{"Accept-Encoding": "gzip, br"}, status: 200, expected: "br js", response: map[string]string{"Content-Encoding": "br", "Vary": "Accept-Encoding"}},
+		{num: 5, path: "/static/app.3f2a1b9c.js", headers: map[string]string{"Accept-Encoding": "gzip, br;q=0"}, status: 200, expected: "gzip js", response: map[string]string{"Content-Encoding": "gzip"}},
+		{num: 6, path: "/static/empty/", status: 404, expected: "not found"},
+		{num: 7, path: "/static/docs", status: 301, response: map[string]string{"Location": "docs/"}},
+		{num: 8, path: "/static/other", status: 404, expected: "not found"},
+		{num: 9, path: "/static/%2e%2e/%2e%2e/etc/passwd", status: 404, expected: "not found"},
+		{num: 10, path: "/listing/empty/", status: 200, expected: "<a href=\"file.txt\">file.txt</a>\n<a href=\"nested/\">nested/</a>"},
+		{num: 11, path: "/app/users/12", status: 200, expected: "index"},
+		{num: 12, path: "/app/missing.js", status: 404, expected: "not found"},
+		{num: 13, path: "/static/odd%20%3F%23%25", status: 301, response: map[string]string{"Location": "odd%20%3F%23%25/"}},
+	}
+	for _, cased := range cases {
+		var req = httptest.NewRequest(http.MethodGet, cased.path, nil)
+		for key, value := range cased.headers {
+			req.Header.Set(key, value)
+		}
+		var rec = httptest.NewRecorder()
+		root.ServeHTTP(rec, req)
+		if rec.Code != cased.status {
+			t.Fatalf("case num: %v | expected status: %v | got: %v", cased.num, cased.status, rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), cased.expected) {
+			t.Fatalf("case num: %v | expected body: %v | got: %v", cased.num, cased.expected, rec.Body.String())
+		}
+		for key, value := range cased.response {
+			if got := rec.Header().Get(key); got != value {
+				t.Fatalf("case num: %v | header: %v | expected: %v | got: %v", cased.num, key, value, got)
+			}
+		}
+	}
+
+	// ETag.
+	var req = httptest.NewRequest(http.MethodGet, "/static/docs/readme.txt", nil)
+	var rec = httptest.NewRecorder()
+	root.ServeHTTP(rec, req)
+	var etag = rec.Header().Get("ETag")
+	if len(etag) < 1 {
+		t.Fatal("expected ETag")
+	}
+	req = httptest.NewRequest(http.MethodGet, "/static/docs/readme.txt", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	root.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("expected status: %v | got: %v", http.StatusNotModified, rec.Code)
+	}
+
+	// only GET / HEAD.
+	req = httptest.NewRequest(http.MethodPost, "/static/docs/readme.txt", nil)
+	rec = httptest.NewRecorder()
+	root.ServeHTTP(rec, req)
+	if allow := rec.Header().Get("Allow"); allow != "GET, HEAD" {
+		t.Fatalf("expected Allow: GET, HEAD | got: %v", allow)
+	}
+}
+
+// file system where precompressed files can't be opened.
+type brokenVariantFS struct {
+	fstest.MapFS
+}
+
+func (f brokenVariantFS) Open(name string) (fs.File, error) {
+	if strings.HasSuffix(name, ".br") {
+		return nil, fs.ErrPermission
+	}
+	return f.MapFS.Open(name)
+}
+
+func TestRouting_StaticBrokenVariant(t *testing.T) {
+	var fsys = brokenVariantFS{MapFS: fstest.MapFS{
+		"app.js":    {Data: []byte("js")},
+		"app.js.br": {Data: []byte("br js")},
+	}}
+	var root = New()
+	root.Static("/static", fsys, &StaticOptions{Precompressed: true})
+	var req = httptest.NewRequest(http.MethodGet, "/static/app.js", nil)
+	req.Header.Set("Accept-Encoding", "br")
+	var rec = httptest.NewRecorder()
+	root.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status: %v | got: %v", http.StatusNotFound, rec.Code)
+	}
+	if encoding := rec.Header().Get("Content-Encoding"); len(encoding) > 0 {
+		t.Fatalf("expected error response without Content-Encoding | got: %v", encoding)
+	}
+}
@@ -0,0 +1,77 @@
+package goway
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// make router (with its groups and routes) read-only:
+// registration methods (Route, Group, Use, Methods, etc) panic.
+//
+// Frozen router can be served by many goroutines without data races.
+// To change routes at runtime - build new router and publish it by Swap.
+func (r *Router) Freeze() *Router {
+	atomic.StoreInt32(&r.root().frozen, 1)
+	return r
+}
+
+// is router read-only (see Freeze)?
+func (r *Router) isFrozen() bool {
+	return atomic.LoadInt32(&r.root().frozen) == 1
+}
+
+// panics if router read-only.
+func (r *Router) checkFrozen() {
+	if r.isFrozen() {
+		panic("goway: router frozen, build new router and use Swap to change routes")
+	}
+}
+
+// panics if route router read-only.
+func (r *Route) checkFrozen() {
+	if r.router != nil {
+		r.router.checkFrozen()
+	}
+}
+
+// atomically replace routes: requests served by next router
+// (instead of router or router from previous Swap).
+//
+// Build next router (New, Route, Group, etc) before Swap,
+// it becomes frozen (see Freeze). Requests that already
+// served by old router not interrupted.
+//
+// Swap(nil) - serve requests by router itself again. Root routers only.
+func (r *Router) Swap(next *Router) {
+	if r.parent != nil || (next != nil && next.parent != nil) {
+		panic("goway: only root routers can be swapped")
+	}
+	if next != nil && next.Current() != next {
+		panic("goway: router with swapped routes can't be used in Swap")
+	}
+	if next != nil {
+		next.Freeze()
+	}
+	// typed nil can be stored.
+	r.live.Store(next)
+}
+
+// get router that serves requests: router from last Swap or router itself.
+//
+// Use it to build URLs or walk routes of live router.
+func (r *Router) Current() *Router {
+	if live, _ := r.live.Load().(*Router); live != nil {
+		return live
+	}
+	return r
+}
+
+// serve request by router from last Swap. Returns false if router not swapped.
+func (r *Router) serveLive(response http.ResponseWriter, request *http.Request) bool {
+	var current = r.Current()
+	if current == r {
+		return false
+	}
+	current.ServeHTTP(response, request)
+	return true
+}
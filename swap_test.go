@@ -0,0 +1,117 @@
+package goway
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestRouter_Freeze(t *testing.T) {
+	var handler = func(w http.ResponseWriter, r *http.Request) {}
+	var root = New()
+	var api = root.Group("/api")
+	var route = api.Route("/users", handler)
+	root.Freeze()
+
+	var isPanics = func(fn func()) (isPanic bool) {
+		defer func() {
+			isPanic = recover() != nil
+		}()
+		fn()
+		return
+	}
+	var registrations = []func(){
+		func() { root.Route("/", handler) },
+		func() { root.Group("/v2") },
+		func() { root.Use(nil) },
+		func() { api.Methods(http.MethodGet) },
+		func() { api.Host("example.com") },
+		func() { api.Mount("/debug", http.NotFoundHandler()) },
+		func() { route.Methods(http.MethodGet) },
+		func() { route.Name("users") },
+		func() { route.Headers("X-Test", "") },
+	}
+	for i, register := range registrations {
+		if !isPanics(register) {
+			t.Fatalf("case num: %v | expected panic on frozen router", i+1)
+		}
+	}
+
+	// still served.
+	var recorder = httptest.NewRecorder()
+	root.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+	if recorder.Code != 200 {
+		t.Fatalf("expected: 200 | got: %v", recorder.Code)
+	}
+}
+
+func TestRouter_Swap(t *testing.T) {
+	var newVersion = func(version int) *Router {
+		var router = New().NotFound(getDefaultHandler404())
+		router.Route("/version", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, version)
+		}).Name("version")
+		router.Route(fmt.Sprintf("/v%v", version), func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, version)
+		})
+		return router
+	}
+	var root = newVersion(0)
+	var get = func(path string) (int, string) {
+		var recorder = httptest.NewRecorder()
+		root.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder.Code, recorder.Body.String()
+	}
+
+	// hot swaps while serving.
+	var server = httptest.NewServer(root)
+	defer server.Close()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				var response, err = http.Get(server.URL + "/version")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				io.Copy(io.Discard, response.Body)
+				response.Body.Close()
+				if response.StatusCode != 200 {
+					t.Errorf("expected: 200 | got: %v", response.StatusCode)
+				}
+			}
+		}()
+	}
+	for version := 1; version <= 20; version++ {
+		root.Swap(newVersion(version))
+	}
+	wg.Wait()
+
+	if code, body := get("/version"); code != 200 || body != "20" {
+		t.Fatalf("expected: 200 20 | got: %v %v", code, body)
+	}
+	if code, body := get("/v0"); code != 404 {
+		t.Fatalf("expected: 404 | got: %v %v", code, body)
+	}
+	if code, _ := get("/v20"); code != 200 {
+		t.Fatalf("expected: 200 | got: %v", code)
+	}
+	if !root.Current().isFrozen() {
+		t.Fatalf("swapped router should be frozen")
+	}
+	if built, err := root.Current().URL("version"); err != nil || built.Path != "/version" {
+		t.Fatalf("expected: /version | got: %v, %v", built, err)
+	}
+
+	// back to own routes.
+	root.Swap(nil)
+	if code, body := get("/v0"); code != 200 || body != "0" {
+		t.Fatalf("expected: 200 0 | got: %v %v", code, body)
+	}
+}
@@ -0,0 +1,279 @@
+package goway
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// route tree node.
+//
+// every router compiles paths of its groups and routes
+// into prefix (radix) tree keyed by path pieces.
+// So instead of checking every group / route,
+// matcher walks request path pieces once.
+//
+// example: routes /users, /users/{id}, /users/me
+//
+//	root
+//	└── users (/users)
+//	    ├── me (/users/me)
+//	    └── {id} (/users/{id})
+//
+// Matching priority: static pieces, then variables, then catch-all.
+// Variables with failed constraint (like {id:int}) not matched.
+type node struct {
+	// path piece like: users or {id}.
+	piece string
+
+	// if node is route variable - variable name.
+	varName string
+
+	// if node is route variable - variable constraint (if exists).
+	constraint *regexp.Regexp
+
+	// static children by path piece.
+	static map[string]*node
+
+	// variable children in registration order.
+	vars []*node
+
+	// catch-all children (like {path...}) in registration order.
+	// Catch-all nodes always leafs.
+	catchAll []*node
+
+	// groups with prefix ending on this node (registration order).
+	groups []*Router
+
+	// routes with path ending on this node (registration order).
+	routes []*Route
+
+	// routes by allowed method, with routes allowing any method
+	// (registration order). Filled by indexMethods.
+	methods map[string][]*Route
+
+	// routes allowing any method (registration order).
+	anyMethod []*Route
+}
+
+// route variable (name + value).
+type routeVar struct {
+	name  string
+	value string
+}
+
+// calls when tree found node with groups / routes.
+//
+// Return true to stop searching.
+type nodeVisitor func(found *node, vars []routeVar) (stop bool)
+
+// create new tree root.
+func newTree() *node {
+	return &node{}
+}
+
+// get node by path slice. Creates nodes if not exists.
+func (n *node) insert(pathSlice []string) *node {
+	var current = n
+	for _, piece := range pathSlice {
+		current = current.child(piece)
+	}
+	return current
+}
+
+// get child by path piece. Creates child if not exists.
+func (n *node) child(piece string) *node {
+	var isCatchAll, catchAllName = isCatchAllVar(piece)
+	if isCatchAll {
+		for _, child := range n.catchAll {
+			if child.piece == piece {
+				return child
+			}
+		}
+		var created = &node{piece: piece, varName: catchAllName}
+		n.catchAll = append(n.catchAll, created)
+		return created
+	}
+
+	var isVar, name, constraint = parseRouteVar(piece)
+	if isVar {
+		for _, child := range n.vars {
+			if child.piece == piece {
+				return child
+			}
+		}
+		var created = &node{
+			piece:      piece,
+			varName:    name,
+			constraint: compileConstraint(constraint),
+		}
+		n.vars = append(n.vars, created)
+		return created
+	}
+
+	if n.static == nil {
+		n.static = make(map[string]*node)
+	}
+	var found, ok = n.static[piece]
+	if !ok {
+		found = &node{piece: piece}
+		n.static[piece] = found
+	}
+	return found
+}
+
+// add group to tree.
+func (n *node) addGroup(group *Router) {
+	var found = n.insert(group.prefix.pathSlice)
+	found.groups = append(found.groups, group)
+}
+
+// add route to tree.
+func (n *node) addRoute(route *Route) {
+	var found = n.insert(route.prefix.pathSlice)
+	if route.isMount {
+		// mounted handler matches all paths under route path.
+		found = found.child(mountPiece)
+	}
+	found.routes = append(found.routes, route)
+	route.node = found
+	found.indexMethods()
+}
+
+// fill routes by method. Call it when routes or its methods changed.
+func (n *node) indexMethods() {
+	n.methods = make(map[string][]*Route)
+	n.anyMethod = nil
+	for _, route := range n.routes {
+		if route.allowedMethods == nil {
+			// any method: add to all lists.
+			n.anyMethod = append(n.anyMethod, route)
+			for method := range n.methods {
+				n.methods[method] = append(n.methods[method], route)
+			}
+			continue
+		}
+		for _, method := range route.allowedMethods {
+			if _, ok := n.methods[method]; !ok {
+				// routes with any method registered before.
+				n.methods[method] = append(make([]*Route, 0), n.anyMethod...)
+			}
+			n.methods[method] = append(n.methods[method], route)
+		}
+	}
+}
+
+// get routes that can serve method (registration order).
+func (n *node) routesFor(method string) []*Route {
+	if routes, ok := n.methods[method]; ok {
+		return routes
+	}
+	return n.anyMethod
+}
+
+// get static children sorted by path piece (stable order for reports).
+func (n *node) staticChildren() []*node {
+	var pieces = make([]string, 0, len(n.static))
+	for piece := range n.static {
+		pieces = append(pieces, piece)
+	}
+	sort.Strings(pieces)
+	var children = make([]*node, 0, len(pieces))
+	for _, piece := range pieces {
+		children = append(children, n.static[piece])
+	}
+	return children
+}
+
+// is path piece satisfies variable constraint?
+func (n *node) isVarMatch(piece string) bool {
+	if n.constraint == nil {
+		return true
+	}
+	return n.constraint.MatchString(piece)
+}
+
+// find nodes with groups, whose prefix is start of pathSlice.
+//
+// Order: static pieces first, then variables. Longest prefixes first.
+func (n *node) lookupGroups(pathSlice []string, vars []routeVar, visit nodeVisitor) (stop bool) {
+	if n == nil {
+		return
+	}
+	if len(pathSlice) > 0 {
+		var piece = pathSlice[0]
+		var rest = pathSlice[1:]
+		if found, ok := n.static[piece]; ok {
+			if found.lookupGroups(rest, vars, visit) {
+				return true
+			}
+		}
+		for _, child := range n.vars {
+			if !child.isVarMatch(piece) {
+				continue
+			}
+			var withVar = append(vars, routeVar{name: child.varName, value: piece})
+			if child.lookupGroups(rest, withVar, visit) {
+				return true
+			}
+		}
+	}
+	if len(n.groups) > 0 {
+		return visit(n, vars)
+	}
+	return
+}
+
+// find nodes with routes, whose path equals pathSlice.
+//
+// Order: static pieces first, then variables, then catch-all.
+func (n *node) lookupRoutes(pathSlice []string, vars []routeVar, visit nodeVisitor) (stop bool) {
+	if n == nil {
+		return
+	}
+	if len(pathSlice) < 1 {
+		if len(n.routes) > 0 && visit(n, vars) {
+			return true
+		}
+		// catch-all also matches empty rest of path.
+		return n.lookupCatchAll(pathSlice, vars, visit)
+	}
+	var piece = pathSlice[0]
+	var rest = pathSlice[1:]
+	if found, ok := n.static[piece]; ok {
+		if found.lookupRoutes(rest, vars, visit) {
+			return true
+		}
+	}
+	for _, child := range n.vars {
+		if !child.isVarMatch(piece) {
+			continue
+		}
+		var withVar = append(vars, routeVar{name: child.varName, value: piece})
+		if child.lookupRoutes(rest, withVar, visit) {
+			return true
+		}
+	}
+	return n.lookupCatchAll(pathSlice, vars, visit)
+}
+
+// visit catch-all children with routes. Variable value is rest of path.
+func (n *node) lookupCatchAll(pathSlice []string, vars []routeVar, visit nodeVisitor) (stop bool) {
+	if len(n.catchAll) < 1 {
+		return
+	}
+	var value = strings.Join(pathSlice, "/")
+	for _, child := range n.catchAll {
+		if len(child.routes) < 1 {
+			continue
+		}
+		var withVar = vars
+		if len(child.varName) > 0 {
+			withVar = append(vars, routeVar{name: child.varName, value: value})
+		}
+		if visit(child, withVar) {
+			return true
+		}
+	}
+	return
+}
@@ -0,0 +1,235 @@
+package goway
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestTree_Routes(t *testing.T) {
+	var root = New()
+	var handler = func(w http.ResponseWriter, r *http.Request) {}
+	var byID = root.Route("/users/{id}", handler)
+	var me = root.Route("/users/me", handler)
+	var users = root.Route("/users", handler)
+	var settings = root.Route("/users/{id}/settings", handler)
+	var files = root.Route("/users/{id}/files/{path...}", handler)
+	var rest = root.Route("/users/{id}/{rest...}", handler)
+
+	type caser struct {
+		num      int
+		path     string
+		expected *Route
+		vars     []routeVar
+	}
+	var cases = []caser{
+		{num: 1, path: "/users", expected: users},
+		{num: 2, path: "/users/me", expected: me},
+		{num: 3, path: "/users/12", expected: byID, vars: []routeVar{{name: "id", value: "12"}}},
+		{num: 4, path: "/users/12/settings", expected: settings, vars: []routeVar{{name: "id", value: "12"}}},
+		{num: 5, path: "/users/me/settings", expected: settings, vars: []routeVar{{name: "id", value: "me"}}},
+		{num: 6, path: "/users/12/files/a/b.txt", expected: files, vars: []routeVar{{name: "id", value: "12"}, {name: "path", value: "a/b.txt"}}},
+		{num: 7, path: "/users/12/files", expected: files, vars: []routeVar{{name: "id", value: "12"}, {name: "path", value: ""}}},
+		{num: 8, path: "/users/12/other/path", expected: rest, vars: []routeVar{{name: "id", value: "12"}, {name: "rest", value: "other/path"}}},
+		{num: 9, path: "/users/12/settings/other", expected: rest, vars: []routeVar{{name: "id", value: "12"}, {name: "rest", value: "settings/other"}}},
+		{num: 10, path: "/", expected: nil},
+		{num: 11, path: "/other", expected: nil},
+	}
+	for _, cased := range cases {
+		var found *Route
+		var foundVars []routeVar
+		root.tree.lookupRoutes(splitPath(cased.path), nil, func(n *node, vars []routeVar) bool {
+			found = n.routes[0]
+			foundVars = append(foundVars, vars...)
+			return true
+		})
+		if found != cased.expected {
+			t.Fatalf("case num: %v | wrong route", cased.num)
+		}
+		if !reflect.DeepEqual(foundVars, cased.vars) {
+			t.Fatalf("case num: %v | expected vars: %v | got: %v", cased.num, cased.vars, foundVars)
+		}
+	}
+}
+
+func TestTree_Constraints(t *testing.T) {
+	var root = New()
+	var handler = func(w http.ResponseWriter, r *http.Request) {}
+	var byID = root.Route("/users/{id:int}", handler)
+	var byToken = root.Route("/users/{token:uuid}", handler)
+	var bySlug = root.Route("/users/{slug:slug}", handler)
+	var byCode = root.Route("/users/{code:[A-Z]{3}}", handler)
+	var byName = root.Route("/users/{name:alpha}/profile", handler)
+	var other = root.Route("/users/{other}", handler)
+
+	type caser struct {
+		num      int
+		path     string
+		expected *Route
+	}
+	var cases = []caser{
+		{num: 1, path: "/users/12", expected: byID},
+		{num: 2, path: "/users/-12", expected: byID},
+		{num: 3, path: "/users/123e4567-e89b-12d3-a456-426614174000", expected: byToken},
+		{num: 4, path: "/users/hello-world", expected: bySlug},
+		{num: 5, path: "/users/ABC", expected: byCode},
+		{num: 6, path: "/users/ABCD", expected: other},
+		{num: 7, path: "/users/Hello_World", expected: other},
+		{num: 8, path: "/users/oklookat/profile", expected: byName},
+		{num: 9, path: "/users/12/profile", expected: nil},
+	}
+	for _, cased := range cases {
+		var found *Route
+		root.tree.lookupRoutes(splitPath(cased.path), nil, func(n *node, vars []routeVar) bool {
+			found = n.routes[0]
+			return true
+		})
+		if found != cased.expected {
+			t.Fatalf("case num: %v | wrong route", cased.num)
+		}
+	}
+
+	// invalid expression.
+	defer func() {
+		if recover() == nil {
+			t.Fatal("invalid constraint should panic")
+		}
+	}()
+	root.Route("/users/{id:[0-9}", handler)
+}
+
+func TestTree_Groups(t *testing.T) {
+	var root = New()
+	var api = root.Group("/api")
+	var v1 = root.Group("/api/v1")
+	var user = root.Group("/api/{user}")
+
+	type caser struct {
+		num      int
+		path     string
+		expected []*Router
+	}
+	var cases = []caser{
+		{num: 1, path: "/api", expected: []*Router{api}},
+		{num: 2, path: "/api/v1/users", expected: []*Router{v1, user, api}},
+		{num: 3, path: "/api/oklookat", expected: []*Router{user, api}},
+		{num: 4, path: "/other", expected: nil},
+	}
+	for _, cased := range cases {
+		var found []*Router
+		root.tree.lookupGroups(splitPath(cased.path), nil, func(n *node, vars []routeVar) bool {
+			found = append(found, n.groups...)
+			return false
+		})
+		if !reflect.DeepEqual(found, cased.expected) {
+			t.Fatalf("case num: %v | wrong groups order", cased.num)
+		}
+	}
+}
+
+// build router with many routes like: /resource0/{id}/action0.
+func benchmarkRouter(resources int, actions int) *Router {
+	var root = New()
+	var handler = func(w http.ResponseWriter, r *http.Request) {}
+	for i := 0; i < resources; i++ {
+		for j := 0; j < actions; j++ {
+			root.Route(fmt.Sprintf("/resource%v/{id}/action%v", i, j), handler)
+		}
+	}
+	return root
+}
+
+// old routeMatcher.Routes (before tree): check every route,
+// every route variable copies request (see legacyAddVarToContext).
+func legacyMatchRoutes(request *http.Request, routes []*Route) (matched *Route, statusCode int) {
+	var requestPath = pathToStandart(request.URL.Path)
+	var requestPathSlice = splitPath(requestPath)
+	statusCode = 200
+	for i := range routes {
+		if routes[i].prefix.excludeCount > 0 {
+			requestPathSlice = routes[i].prefix.getExcluded(requestPath)
+		}
+		if len(routes[i].prefix.pathSlice) != len(requestPathSlice) {
+			continue
+		}
+		if legacyMatchPathPieces(request, routes[i].prefix.pathSlice, requestPathSlice) {
+			matched = routes[i]
+		}
+		var isLastRoute = i == len(routes)-1
+		if matched == nil {
+			if !isLastRoute {
+				continue
+			}
+			if statusCode != 405 {
+				statusCode = 404
+			}
+			return
+		}
+		if isMethodAllowed(matched.allowedMethods, request.Method) {
+			statusCode = 0
+			return
+		}
+		statusCode = 405
+		if isLastRoute {
+			matched = nil
+			return
+		}
+	}
+	return
+}
+
+// old routeMatcher.matchPathPieces: adds variables to request while comparing.
+func legacyMatchPathPieces(request *http.Request, pathSlice []string, requestPathSlice []string) (matched bool) {
+	if isPathSliceEmpty(pathSlice) && isPathSliceEmpty(requestPathSlice) {
+		return true
+	}
+	for pieceCounter := range pathSlice {
+		var pathPiece = pathSlice[pieceCounter]
+		var requestPathPiece = requestPathSlice[pieceCounter]
+		if pathPiece != requestPathPiece {
+			var isVar, name = isRouteVar(pathPiece)
+			if !isVar {
+				return false
+			}
+			legacyAddVarToContext(request, name, requestPathPiece)
+		}
+		if pieceCounter == len(pathSlice)-1 {
+			return true
+		}
+	}
+	return
+}
+
+// current matcher: split path, lookup tree, add variables to request.
+func BenchmarkMatch_Tree(b *testing.B) {
+	var root = benchmarkRouter(50, 10)
+	var base = httptest.NewRequest(http.MethodGet, "/resource49/12/action9", nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var matcher = getMatcher(base)
+		matcher.Match(root)
+		if matcher.result.route == nil {
+			b.Fatal("route not found")
+		}
+		attachVars(base, &matcher.result, matcher.vars)
+		matcher.release()
+	}
+}
+
+// matcher before tree.
+func BenchmarkMatch_Linear(b *testing.B) {
+	var root = benchmarkRouter(50, 10)
+	var base = httptest.NewRequest(http.MethodGet, "/resource49/12/action9", nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var request = *base
+		var found, _ = legacyMatchRoutes(&request, root.routes)
+		if found == nil {
+			b.Fatal("route not found")
+		}
+	}
+}
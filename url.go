@@ -0,0 +1,156 @@
+package goway
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var (
+	// route with this name not exists.
+	ErrUnknownRouteName = errors.New("goway: unknown route name")
+
+	// more than one route with this name.
+	ErrDuplicateRouteName = errors.New("goway: duplicate route name")
+
+	// wrong route variables for URL building.
+	ErrWrongVars = errors.New("goway: wrong route variables")
+)
+
+// register route name.
+func (r *Router) addName(name string, route *Route) {
+	if r.names == nil {
+		r.names = make(map[string]*Route)
+	}
+
+	// route renamed.
+	if len(route.name) > 0 && r.names[route.name] == route {
+		delete(r.names, route.name)
+	}
+
+	var existing, ok = r.names[name]
+	if ok && existing != route {
+		if r.duplicateNames == nil {
+			r.duplicateNames = make(map[string]bool)
+		}
+		r.duplicateNames[name] = true
+		return
+	}
+	r.names[name] = route
+}
+
+// build URL of named route.
+//
+// pairs - route variables like: "id", "12", "path", "a/b.txt".
+//
+// Returns URL with path (like: /api/users/12)
+// and host (if route or its groups has host).
+func (r *Router) URL(name string, pairs ...string) (*url.URL, error) {
+	var root = r.root()
+	if root.duplicateNames[name] {
+		return nil, fmt.Errorf("%w: %v", ErrDuplicateRouteName, name)
+	}
+	var route, ok = root.names[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownRouteName, name)
+	}
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("%w: odd number of pairs", ErrWrongVars)
+	}
+	var vars = make(map[string]string, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		vars[pairs[i]] = pairs[i+1]
+	}
+
+	var unescaped strings.Builder
+	var escaped strings.Builder
+	for _, piece := range route.fullPathSlice() {
+		var value, escapedValue, err = buildPathPiece(piece, vars)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", name, err)
+		}
+		unescaped.WriteString("/" + value)
+		escaped.WriteString("/" + escapedValue)
+	}
+
+	var built = &url.URL{
+		Path:    unescaped.String(),
+		RawPath: escaped.String(),
+	}
+	if len(built.Path) < 1 {
+		built.Path = "/"
+		built.RawPath = ""
+	}
+
+	// host.
+	var host = route.getHostPattern()
+	if host != nil {
+		var err error
+		built.Host, err = host.build(vars)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", name, err)
+		}
+	}
+	return built, nil
+}
+
+// get host pattern of route or its groups (if exists).
+func (r *Route) getHostPattern() *hostPattern {
+	var host = getHostPattern(r.matchers)
+	for router := r.router; host == nil && router != nil; router = router.parent {
+		host = getHostPattern(router.matchers)
+	}
+	return host
+}
+
+// get route path slice with prefixes of all parent groups.
+func (r *Route) fullPathSlice() []string {
+	var parents = make([][]string, 0)
+	for router := r.router; router != nil; router = router.parent {
+		parents = append(parents, router.prefix.pathSlice)
+	}
+	var full = make([]string, 0)
+	for i := len(parents) - 1; i >= 0; i-- {
+		full = append(full, parents[i]...)
+	}
+	return append(full, r.prefix.pathSlice...)
+}
+
+// get value of path piece. If piece is variable - checks and escapes it.
+func buildPathPiece(piece string, vars map[string]string) (value string, escaped string, err error) {
+	var isCatchAll, catchAllName = isCatchAllVar(piece)
+	if isCatchAll {
+		var ok bool
+		value, ok = vars[catchAllName]
+		if !ok {
+			err = fmt.Errorf("%w: missing %v", ErrWrongVars, catchAllName)
+			return
+		}
+		value = removeSlashStart(value)
+		var escapedPieces = strings.Split(value, "/")
+		for i := range escapedPieces {
+			escapedPieces[i] = url.PathEscape(escapedPieces[i])
+		}
+		escaped = strings.Join(escapedPieces, "/")
+		return
+	}
+
+	var isVar, name, constraint = parseRouteVar(piece)
+	if !isVar {
+		return piece, piece, nil
+	}
+	var ok bool
+	value, ok = vars[name]
+	if !ok || len(value) < 1 {
+		err = fmt.Errorf("%w: missing %v", ErrWrongVars, name)
+		return
+	}
+	var compiled = compileConstraint(constraint)
+	if compiled != nil && !compiled.MatchString(value) {
+		err = fmt.Errorf("%w: %v not matches %v", ErrWrongVars, name, constraint)
+		return
+	}
+	escaped = url.PathEscape(value)
+	return
+}
@@ -0,0 +1,53 @@
+package goway
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestRouter_URL(t *testing.T) {
+	var root = New()
+	var handler = func(w http.ResponseWriter, r *http.Request) {}
+	root.Route("/", handler).Name("index")
+	var api = root.Group("/api/{version}")
+	api.Route("/users/{id:int}", handler).Name("user")
+	api.Group("files").Route("/{path...}", handler).Name("file")
+	api.Route("/search/{query}", handler).Name("search")
+	root.Route("/dup1", handler).Name("dup")
+	api.Route("/dup2", handler).Name("dup")
+
+	type caser struct {
+		num      int
+		name     string
+		pairs    []string
+		expected string
+		err      error
+	}
+	var cases = []caser{
+		{num: 1, name: "index", expected: "/"},
+		{num: 2, name: "user", pairs: []string{"version", "v1", "id", "12"}, expected: "/api/v1/users/12"},
+		{num: 3, name: "file", pairs: []string{"version", "v1", "path", "a b/c.txt"}, expected: "/api/v1/files/a%20b/c.txt"},
+		{num: 4, name: "search", pairs: []string{"version", "v1", "query", "a/b?c"}, expected: "/api/v1/search/a%2Fb%3Fc"},
+		{num: 5, name: "user", pairs: []string{"version", "v1", "id", "me"}, err: ErrWrongVars},
+		{num: 6, name: "user", pairs: []string{"version", "v1"}, err: ErrWrongVars},
+		{num: 7, name: "user", pairs: []string{"version"}, err: ErrWrongVars},
+		{num: 8, name: "unknown", err: ErrUnknownRouteName},
+		{num: 9, name: "dup", err: ErrDuplicateRouteName},
+	}
+	for _, cased := range cases {
+		var built, err = api.URL(cased.name, cased.pairs...)
+		if cased.err != nil {
+			if !errors.Is(err, cased.err) {
+				t.Fatalf("case num: %v | expected error: %v | got: %v", cased.num, cased.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("case num: %v | %v", cased.num, err)
+		}
+		if built.String() != cased.expected {
+			t.Fatalf("case num: %v | expected: %v | got: %v", cased.num, cased.expected, built.String())
+		}
+	}
+}
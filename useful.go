@@ -8,20 +8,37 @@ import (
 type MiddlewareFunc func(http.Handler) http.Handler
 
 // route endpoint.
+//
+// http.Handler can be passed where RouteHandler expected by method value,
+// like: root.NotFound(handler.ServeHTTP). Or see Router.Handle.
 type RouteHandler func(http.ResponseWriter, *http.Request)
 
+// calls handler. RouteHandler can be used as http.Handler (like http.HandlerFunc).
+func (h RouteHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	h(response, request)
+}
+
 // vars for request context.
 type CTX_VAL string
 
 const (
-	// route variables.
+	// route variables (use Vars or Param to get it).
 	CTX_VARS_NAME CTX_VAL = "GOWAY_ROUTER_VARS"
+
+	// allowed methods (when request method not allowed).
+	CTX_ALLOWED_METHODS_NAME CTX_VAL = "GOWAY_ROUTER_ALLOWED_METHODS"
 )
 
 // when route not found.
+//
+// Used by routers without own handler (see Router.NotFound).
 var Handler404 = getDefaultHandler404()
 
 // when request method not allowed.
+//
+// Used by routers without own handler (see Router.MethodNotAllowed).
+//
+// Allow header already set. Use AllowedMethods() to get allowed methods.
 var Handler405 = getDefaultHandler405()
 
 // tools for working on route/group paths.
@@ -68,8 +85,14 @@ func (p *prefixes) setPath(to string) {
 	p.path = pathToStandart(to)
 }
 
+// how much pieces should be removed from request path
+// to get path after this prefix.
+func (p *prefixes) getStripCount() int {
+	return p.excludeCount + len(p.pathSlice)
+}
+
 // set pathSlice by path.
 func (p *prefixes) setPathSlice() {
-	var splitted = splitPath(p.path)
+	var splitted = splitPattern(p.path)
 	p.pathSlice = splitted
 }
@@ -7,31 +7,15 @@ import (
 	"strings"
 )
 
-// when ServeHTTP called, sets Executed to true.
-type dummyEndpoint struct {
-	Executed bool
-}
-
-func (d *dummyEndpoint) ServeHTTP(response http.ResponseWriter, request *http.Request) {
-	d.Executed = true
-}
-
-// run middlewares.
-func executeMiddleware(response http.ResponseWriter, request *http.Request, middleware MiddlewareFunc) (isResponseSended bool) {
+// wrap endpoint with middleware.
+//
+// Middleware decides by itself: call next.ServeHTTP() (maybe with
+// another request / response writer) or send response.
+func wrapMiddleware(middleware MiddlewareFunc, endpoint http.Handler) http.Handler {
 	if middleware == nil {
-		return
+		return nil
 	}
-
-	// set dummy as middleware endpoint.
-	var dummy = &dummyEndpoint{}
-	var handler = middleware(dummy)
-	handler.ServeHTTP(response, request)
-
-	// if dummy not executed
-	// it means middleware sended response / not called next.ServeHTTP()
-	isResponseSended = !dummy.Executed
-
-	return
+	return middleware(endpoint)
 }
 
 // https://gist.github.com/husobee/fd23681261a39699ee37?permalink_comment_id=3111569#gistcomment-3111569
@@ -49,37 +33,77 @@ func middlewareChain(middlewares ...MiddlewareFunc) MiddlewareFunc {
 	}
 }
 
-// get path variables.
+// get path variables (copy). Use Param to get variable without allocations.
 func Vars(request *http.Request) map[string]string {
-	var ctx = request.Context()
-	var varsMap, ok = ctx.Value(CTX_VARS_NAME).(map[string]string)
-	if !ok {
+	var found = getParams(request)
+	if found == nil {
 		return nil
 	}
+	var varsMap = make(map[string]string, len(found.vars))
+	for _, v := range found.vars {
+		varsMap[v.name] = v.value
+	}
 	return varsMap
 }
 
-// is route variable?
+// is route variable? Returns variable name without constraint.
 func isRouteVar(path string) (isVar bool, varName string) {
-	isVar = strings.HasPrefix(path, "{") && strings.HasSuffix(path, "}")
-	if isVar {
-		var withoutBrackets = strings.ReplaceAll(path, "{", "")
-		withoutBrackets = strings.ReplaceAll(withoutBrackets, "}", "")
-		varName = withoutBrackets
+	isVar, varName, _ = parseRouteVar(path)
+	return
+}
+
+// parse route variable like {id} or {id:int}.
+func parseRouteVar(path string) (isVar bool, varName string, constraint string) {
+	isVar = len(path) > 1 && strings.HasPrefix(path, "{") && strings.HasSuffix(path, "}")
+	if !isVar || strings.HasSuffix(path, "...}") {
+		// not var or catch-all.
+		return false, "", ""
+	}
+	varName = path[1 : len(path)-1]
+	var index = strings.Index(varName, ":")
+	if index > -1 {
+		constraint = varName[index+1:]
+		varName = varName[:index]
 	}
 	return
 }
 
-// add route variable with value to request context.
-func addVarToContext(request *http.Request, name string, value string) {
-	var oldCtx = request.Context()
-	var varsMap, ok = oldCtx.Value(CTX_VARS_NAME).(map[string]string)
-	if !ok || varsMap == nil {
-		varsMap = make(map[string]string, 0)
+// is catch-all route variable (like {path...} or *)?
+//
+// For * variable name is *.
+func isCatchAllVar(path string) (isCatchAll bool, varName string) {
+	if path == "*" {
+		return true, "*"
+	}
+	isCatchAll = strings.HasPrefix(path, "{") && strings.HasSuffix(path, "...}")
+	if isCatchAll {
+		varName = strings.TrimPrefix(path, "{")
+		varName = strings.TrimSuffix(varName, "...}")
 	}
-	varsMap[name] = value
-	var ctxWithVars = context.WithValue(oldCtx, CTX_VARS_NAME, varsMap)
-	*request = *request.WithContext(ctxWithVars)
+	return
+}
+
+// panics if catch-all not last piece of path slice.
+// Set isPrefix to true if it's group prefix (catch-all not allowed).
+func checkCatchAll(pathSlice []string, isPrefix bool) {
+	for i, piece := range pathSlice {
+		var isCatchAll, _ = isCatchAllVar(piece)
+		if !isCatchAll {
+			continue
+		}
+		if isPrefix {
+			panic("goway: catch-all not allowed in group prefix: " + piece)
+		}
+		if i != len(pathSlice)-1 {
+			panic("goway: catch-all must be last piece of route path: " + piece)
+		}
+	}
+}
+
+// add route variable with value to request (new params, existing params not changed).
+func addVarToContext(request *http.Request, name string, value string) {
+	var variable = routeVar{name: name, value: value}
+	*request = *attachVars(request, nil, []routeVar{variable})
 }
 
 // check is method allowed.
@@ -95,6 +119,21 @@ func isMethodAllowed(methods []string, requestMethod string) bool {
 	return false
 }
 
+// can HEAD request be served by GET handler?
+func isImplicitHead(methods []string, requestMethod string) bool {
+	return requestMethod == http.MethodHead && isMethodAllowed(methods, http.MethodGet)
+}
+
+// add HEAD to methods if GET exists.
+func addImplicitHead(methods []string) []string {
+	for _, method := range methods {
+		if method == http.MethodGet {
+			return processAllowedMethods(methods, http.MethodHead)
+		}
+	}
+	return methods
+}
+
 // remove slash at start and end of str.
 func removeSlashStartEnd(str string) string {
 	if len(str) < 1 {
@@ -118,6 +157,24 @@ func removeSlashEnd(str string) string {
 	return str
 }
 
+// get allowed methods of requested path (available in 405 handler).
+func AllowedMethods(request *http.Request) []string {
+	var ctx = request.Context()
+	var methods, ok = ctx.Value(CTX_ALLOWED_METHODS_NAME).([]string)
+	if !ok {
+		return nil
+	}
+	return methods
+}
+
+// set Allow header and call 405 handler.
+func sendMethodNotAllowed(response http.ResponseWriter, request *http.Request, allowed []string, handler405 RouteHandler) {
+	allowed = addImplicitHead(removeDuplicateValues(allowed))
+	response.Header().Set("Allow", strings.Join(allowed, ", "))
+	var ctx = context.WithValue(request.Context(), CTX_ALLOWED_METHODS_NAME, allowed)
+	handler405(response, request.WithContext(ctx))
+}
+
 // default 405 handler.
 func getDefaultHandler405() RouteHandler {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -194,6 +251,16 @@ func processMiddleware(old MiddlewareFunc, middlewares ...MiddlewareFunc) Middle
 	return finalChain
 }
 
+// count not nil middlewares.
+func countMiddleware(middlewares ...MiddlewareFunc) (count int) {
+	for _, m := range middlewares {
+		if m != nil {
+			count++
+		}
+	}
+	return
+}
+
 func splitPath(path string) []string {
 	path = pathToStandart(path)
 	if len(path) < 1 {
@@ -202,6 +269,41 @@ func splitPath(path string) []string {
 	return strings.Split(removeSlashStartEnd(path), "/")
 }
 
+// like splitPath, but for route / group path:
+// slashes in variable constraints (like {name:[^/]+}) not split.
+//
+// Panics if braces unbalanced.
+func splitPattern(pattern string) []string {
+	var cleaned = pathToStandart(pattern)
+	if len(cleaned) < 1 {
+		return make([]string, 0)
+	}
+	cleaned = removeSlashStartEnd(cleaned)
+	var pieces = make([]string, 0)
+	var depth = 0
+	var start = 0
+	for i := 0; i < len(cleaned); i++ {
+		switch cleaned[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '/':
+			if depth == 0 {
+				pieces = append(pieces, cleaned[start:i])
+				start = i + 1
+			}
+		}
+		if depth < 0 {
+			break
+		}
+	}
+	if depth != 0 {
+		panic("goway: unbalanced braces in path: " + pattern)
+	}
+	return append(pieces, cleaned[start:])
+}
+
 func isPathSliceEmpty(slice []string) bool {
 	return slice == nil || len(slice) < 1 || slice[0] == "" || slice[0] == "."
 }
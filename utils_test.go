@@ -2,6 +2,7 @@ package goway
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
 )
@@ -31,6 +32,123 @@ func TestIsVar(t *testing.T) {
 	}
 }
 
+func TestParseRouteVar(t *testing.T) {
+	type caser struct {
+		num        int
+		value      string
+		isVar      bool
+		name       string
+		constraint string
+	}
+	var cases = []caser{
+		{num: 1, value: "{id}", isVar: true, name: "id"},
+		{num: 2, value: "{id:int}", isVar: true, name: "id", constraint: "int"},
+		{num: 3, value: "{id:[0-9]{3}}", isVar: true, name: "id", constraint: "[0-9]{3}"},
+		{num: 4, value: "{path...}", isVar: false},
+		{num: 5, value: "id", isVar: false},
+	}
+	for _, cased := range cases {
+		var isVar, name, constraint = parseRouteVar(cased.value)
+		if isVar != cased.isVar || name != cased.name || constraint != cased.constraint {
+			t.Fatalf("case num: %v | expected: %v %v %v | got: %v %v %v",
+				cased.num, cased.isVar, cased.name, cased.constraint, isVar, name, constraint)
+		}
+	}
+}
+
+func TestIsCatchAllVar(t *testing.T) {
+	type caser struct {
+		num        int
+		value      string
+		isCatchAll bool
+		name       string
+	}
+	var cases = []caser{
+		{num: 1, value: "{path...}", isCatchAll: true, name: "path"},
+		{num: 2, value: "*", isCatchAll: true, name: "*"},
+		{num: 3, value: "{path}", isCatchAll: false},
+		{num: 4, value: "path...", isCatchAll: false},
+	}
+	for _, cased := range cases {
+		var isCatchAll, name = isCatchAllVar(cased.value)
+		if isCatchAll != cased.isCatchAll || name != cased.name {
+			t.Fatalf("case num: %v | expected: %v %v | got: %v %v", cased.num, cased.isCatchAll, cased.name, isCatchAll, name)
+		}
+	}
+
+	// catch-all is not simple var.
+	var isVar, _ = isRouteVar("{path...}")
+	if isVar {
+		t.Fatal("catch-all should not be simple var")
+	}
+}
+
+func TestCheckCatchAll(t *testing.T) {
+	var isPanicked = func(pathSlice []string, isPrefix bool) (panicked bool) {
+		defer func() {
+			panicked = recover() != nil
+		}()
+		checkCatchAll(pathSlice, isPrefix)
+		return
+	}
+	if isPanicked([]string{"files", "{path...}"}, false) {
+		t.Fatal("catch-all at end should be allowed")
+	}
+	if !isPanicked([]string{"files", "*", "edit"}, false) {
+		t.Fatal("catch-all not at end should panic")
+	}
+	if !isPanicked([]string{"files", "{path...}"}, true) {
+		t.Fatal("catch-all in group prefix should panic")
+	}
+}
+
+func TestSplitPattern(t *testing.T) {
+	type caser struct {
+		num      int
+		pattern  string
+		expected []string
+	}
+	var cases = []caser{
+		{num: 1, pattern: "/files/{name:[^/]+}", expected: []string{"files", "{name:[^/]+}"}},
+		{num: 2, pattern: "/users/{id:[0-9]{2}}/edit/", expected: []string{"users", "{id:[0-9]{2}}", "edit"}},
+		{num: 3, pattern: "/", expected: []string{}},
+		{num: 4, pattern: "/a/{b}/c", expected: []string{"a", "{b}", "c"}},
+	}
+	for _, cased := range cases {
+		if result := splitPattern(cased.pattern); !reflect.DeepEqual(result, cased.expected) {
+			t.Fatalf("case num: %v | expected: %v | got: %v", cased.num, cased.expected, result)
+		}
+	}
+
+	var isPanicked = func(pattern string) (panicked bool) {
+		defer func() {
+			panicked = recover() != nil
+		}()
+		New().Route(pattern, func(w http.ResponseWriter, r *http.Request) {})
+		return
+	}
+	for _, pattern := range []string{"/files/{name:[^/]+", "/files/name}", "/files/}{"} {
+		if !isPanicked(pattern) {
+			t.Fatalf("pattern: %v | expected panic", pattern)
+		}
+	}
+}
+
+func TestRouting_SlashConstraint(t *testing.T) {
+	var root = New()
+	root.Route("/files/{name:[^/]+}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(Param(r, "name")))
+	})
+	var cases = map[string]int{"/files/a.txt": 200, "/files/a/b": 404}
+	for path, status := range cases {
+		var recorder = httptest.NewRecorder()
+		root.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != status {
+			t.Fatalf("path: %v | expected: %v | got: %v", path, status, recorder.Code)
+		}
+	}
+}
+
 // test request set/get variables.
 func TestGetSetVars(t *testing.T) {
 	var req, err = http.NewRequest(http.MethodGet, "http://127.0.0.1", nil)
@@ -0,0 +1,390 @@
+package goway
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// route with same path (variable names not counted) and methods registered before.
+	ErrDuplicateRoute = errors.New("goway: duplicate route")
+
+	// route never matched: all its requests matched by group / route
+	// registered before (like /users/{name} after /users/{id}).
+	ErrShadowedRoute = errors.New("goway: shadowed route")
+
+	// some requests can be matched by both routes,
+	// and result depends on registration order (like /{id:int} and /{slug:slug}).
+	ErrAmbiguousRoute = errors.New("goway: ambiguous route")
+)
+
+// route registration problem.
+type RouteConflict struct {
+	// ErrDuplicateRoute, ErrShadowedRoute or ErrAmbiguousRoute.
+	Kind error
+
+	// route with problem.
+	Route RouteInfo
+
+	// route or group (only Path filled) that conflicts with Route.
+	Other RouteInfo
+}
+
+func (r *RouteConflict) Error() string {
+	return fmt.Sprintf("%v: %v %v conflicts with %v %v",
+		r.Kind, strings.Join(r.Route.Methods, ","), r.Route.Path,
+		strings.Join(r.Other.Methods, ","), r.Other.Path)
+}
+
+func (r *RouteConflict) Unwrap() error {
+	return r.Kind
+}
+
+// find duplicate, shadowed and ambiguous routes of router and its groups.
+//
+// Routes and groups with request matchers (Host, Headers, etc)
+// can't shadow other routes.
+//
+// Returns nil if no problems. Errors are *RouteConflict.
+func (r *Router) Validate() []error {
+	var errs []error
+	var routes = make([]*Route, 0)
+	r.walkRoutes(func(route *Route) {
+		routes = append(routes, route)
+	})
+	for _, route := range routes {
+		if err := route.findShadow(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	r.walkRouters(func(router *Router) {
+		errs = append(errs, router.tree.findAmbiguous(0)...)
+	})
+	return errs
+}
+
+// like Validate, but panics if problems found. Call it after routes registration.
+func (r *Router) MustValidate() {
+	var errs = r.Validate()
+	if len(errs) < 1 {
+		return
+	}
+	var messages = make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	panic(strings.Join(messages, "\n"))
+}
+
+// calls fn for every route of router and its groups.
+func (r *Router) walkRoutes(fn func(route *Route)) {
+	r.walkRouters(func(router *Router) {
+		for _, route := range router.routes {
+			fn(route)
+		}
+	})
+}
+
+// calls fn for router and all its groups.
+func (r *Router) walkRouters(fn func(router *Router)) {
+	fn(r)
+	for _, group := range r.groups {
+		group.walkRouters(fn)
+	}
+}
+
+// get route path slice for tree (with mount piece if route mounted).
+func (r *Route) templateSlice() []string {
+	var template = r.fullPathSlice()
+	if r.isMount {
+		template = append(template, mountPiece)
+	}
+	return template
+}
+
+// find group / route that matches all requests of route (before route).
+func (r *Route) findShadow() error {
+	// routers from root to route router.
+	var chain = make([]*Router, 0)
+	for router := r.router; router != nil; router = router.parent {
+		chain = append([]*Router{router}, chain...)
+	}
+	var template = r.templateSlice()
+	var methods = r.effectiveMethods()
+	var offset = 0
+	for level, router := range chain {
+		var rest = template[offset:]
+
+		// groups matched first.
+		var next *Router
+		if level < len(chain)-1 {
+			next = chain[level+1]
+		}
+		var shadowGroup *Router
+		router.tree.lookupTemplateGroups(rest, func(found *node) bool {
+			for _, group := range found.groups {
+				if group == next {
+					return true
+				}
+				if len(group.matchers) < 1 && isMethodsCovered(group.allowedMethods, methods) {
+					shadowGroup = group
+					return true
+				}
+			}
+			return false
+		})
+		if shadowGroup != nil {
+			var other = RouteInfo{
+				Path:    "/" + strings.Join(shadowGroup.fullPathSlice(), "/"),
+				Methods: append(make([]string, 0), shadowGroup.effectiveMethods()...),
+			}
+			return &RouteConflict{Kind: ErrShadowedRoute, Route: r.info(), Other: other}
+		}
+		if next != nil {
+			offset += len(next.prefix.pathSlice)
+			continue
+		}
+
+		// routes.
+		var shadowRoute *Route
+		router.tree.lookupTemplateRoutes(rest, func(found *node) bool {
+			for _, route := range found.routes {
+				if route == r {
+					return true
+				}
+				if len(route.matchers) < 1 && isMethodsCovered(route.allowedMethods, methods) {
+					shadowRoute = route
+					return true
+				}
+			}
+			return false
+		})
+		if shadowRoute == nil {
+			return nil
+		}
+		var kind = ErrShadowedRoute
+		if getTemplateShape(shadowRoute.templateSlice()) == getTemplateShape(template) {
+			kind = ErrDuplicateRoute
+		}
+		return &RouteConflict{Kind: kind, Route: r.info(), Other: shadowRoute.info()}
+	}
+	return nil
+}
+
+// get router prefix with prefixes of all parent groups.
+func (r *Router) fullPathSlice() []string {
+	var full = make([]string, 0)
+	for router := r; router != nil; router = router.parent {
+		full = append(append(make([]string, 0), router.prefix.pathSlice...), full...)
+	}
+	return full
+}
+
+// is all target methods allowed by methods? nil - any method.
+func isMethodsCovered(methods []string, target []string) bool {
+	if methods == nil {
+		return true
+	}
+	if target == nil {
+		return false
+	}
+	for _, method := range target {
+		if !isMethodAllowed(methods, method) {
+			return false
+		}
+	}
+	return true
+}
+
+// is node matches all values of route template piece?
+func (n *node) isCovers(piece string) bool {
+	if len(n.varName) < 1 {
+		// static.
+		return n.piece == piece
+	}
+	if isCatchAll, _ := isCatchAllVar(piece); isCatchAll {
+		return false
+	}
+	if n.constraint == nil {
+		return true
+	}
+	var isVar, _, constraint = parseRouteVar(piece)
+	if isVar {
+		return compileConstraint(constraint) == n.constraint
+	}
+	return n.constraint.MatchString(piece)
+}
+
+// like lookupGroups, but for route template:
+// visits groups which matches all requests of template.
+func (n *node) lookupTemplateGroups(template []string, visit func(found *node) (stop bool)) (stop bool) {
+	if len(template) > 0 {
+		var piece = template[0]
+		var rest = template[1:]
+		if found, ok := n.static[piece]; ok {
+			if found.lookupTemplateGroups(rest, visit) {
+				return true
+			}
+		}
+		for _, child := range n.vars {
+			if child.isCovers(piece) && child.lookupTemplateGroups(rest, visit) {
+				return true
+			}
+		}
+	}
+	if len(n.groups) > 0 {
+		return visit(n)
+	}
+	return
+}
+
+// like lookupRoutes, but for route template:
+// visits routes which matches all requests of template.
+func (n *node) lookupTemplateRoutes(template []string, visit func(found *node) (stop bool)) (stop bool) {
+	if len(template) < 1 {
+		if len(n.routes) > 0 && visit(n) {
+			return true
+		}
+		return n.lookupTemplateCatchAll(visit)
+	}
+	var piece = template[0]
+	var rest = template[1:]
+	if found, ok := n.static[piece]; ok {
+		if found.lookupTemplateRoutes(rest, visit) {
+			return true
+		}
+	}
+	for _, child := range n.vars {
+		if child.isCovers(piece) && child.lookupTemplateRoutes(rest, visit) {
+			return true
+		}
+	}
+	return n.lookupTemplateCatchAll(visit)
+}
+
+// visit catch-all children with routes.
+func (n *node) lookupTemplateCatchAll(visit func(found *node) (stop bool)) (stop bool) {
+	for _, child := range n.catchAll {
+		if len(child.routes) > 0 && visit(child) {
+			return true
+		}
+	}
+	return
+}
+
+// built-in types, that never match same value.
+var disjointVarTypes = map[[2]string]bool{
+	{"int", "alpha"}:  true,
+	{"int", "uuid"}:   true,
+	{"alpha", "uuid"}: true,
+}
+
+// can constraints match same value? For regular expressions - maybe.
+func isConstraintsOverlap(first string, second string) bool {
+	return !disjointVarTypes[[2]string{first, second}] && !disjointVarTypes[[2]string{second, first}]
+}
+
+// find routes under constrained variables of one node
+// with same rest of path, like: /{id:int}/edit and /{slug:slug}/edit.
+//
+// depth - node depth in tree.
+func (n *node) findAmbiguous(depth int) (errs []error) {
+	for i, first := range n.vars {
+		for _, second := range n.vars[i+1:] {
+			var _, _, firstConstraint = parseRouteVar(first.piece)
+			var _, _, secondConstraint = parseRouteVar(second.piece)
+			if first.constraint == nil || second.constraint == nil || first.constraint == second.constraint {
+				// clear priority or shadowing.
+				continue
+			}
+			if !isConstraintsOverlap(firstConstraint, secondConstraint) {
+				continue
+			}
+			errs = append(errs, findAmbiguousRoutes(first.subtreeRoutes(), second.subtreeRoutes(), depth+1)...)
+		}
+	}
+	for _, child := range n.staticChildren() {
+		errs = append(errs, child.findAmbiguous(depth+1)...)
+	}
+	for _, child := range n.vars {
+		errs = append(errs, child.findAmbiguous(depth+1)...)
+	}
+	return
+}
+
+// get routes of node and its children.
+func (n *node) subtreeRoutes() []*Route {
+	var routes = append(make([]*Route, 0), n.routes...)
+	for _, child := range n.staticChildren() {
+		routes = append(routes, child.subtreeRoutes()...)
+	}
+	for _, child := range n.vars {
+		routes = append(routes, child.subtreeRoutes()...)
+	}
+	for _, child := range n.catchAll {
+		routes = append(routes, child.routes...)
+	}
+	return routes
+}
+
+// compare routes with same path shape after depth.
+func findAmbiguousRoutes(firstRoutes []*Route, secondRoutes []*Route, depth int) (errs []error) {
+	for _, first := range firstRoutes {
+		for _, second := range secondRoutes {
+			if len(first.matchers) > 0 || len(second.matchers) > 0 {
+				continue
+			}
+			if getPathShape(first, depth) != getPathShape(second, depth) {
+				continue
+			}
+			if len(intersectMethods(first.effectiveMethods(), second.effectiveMethods())) < 1 &&
+				first.effectiveMethods() != nil && second.effectiveMethods() != nil {
+				continue
+			}
+			errs = append(errs, &RouteConflict{Kind: ErrAmbiguousRoute, Route: second.info(), Other: first.info()})
+		}
+	}
+	return
+}
+
+// get route path after depth, with variables replaced by {}.
+func getPathShape(route *Route, depth int) string {
+	var pathSlice = route.prefix.pathSlice
+	if route.isMount {
+		pathSlice = append(append(make([]string, 0), pathSlice...), mountPiece)
+	}
+	if depth > len(pathSlice) {
+		return ""
+	}
+	var shape = make([]string, 0)
+	for _, piece := range pathSlice[depth:] {
+		if isCatchAll, _ := isCatchAllVar(piece); isCatchAll {
+			shape = append(shape, "{...}")
+			continue
+		}
+		if isVar, _ := isRouteVar(piece); isVar {
+			shape = append(shape, "{}")
+			continue
+		}
+		shape = append(shape, piece)
+	}
+	return strings.Join(shape, "/")
+}
+
+// get path template without variable names, like: /users/{:int}/{...}.
+func getTemplateShape(template []string) string {
+	var shape = make([]string, 0, len(template))
+	for _, piece := range template {
+		if isCatchAll, _ := isCatchAllVar(piece); isCatchAll {
+			shape = append(shape, "{...}")
+			continue
+		}
+		if isVar, _, constraint := parseRouteVar(piece); isVar {
+			shape = append(shape, "{:"+constraint+"}")
+			continue
+		}
+		shape = append(shape, piece)
+	}
+	return strings.Join(shape, "/")
+}
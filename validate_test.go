@@ -0,0 +1,140 @@
+package goway
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestRouter_Validate(t *testing.T) {
+	var handler = func(w http.ResponseWriter, r *http.Request) {}
+
+	type caser struct {
+		num int
+
+		// register routes.
+		setup func(root *Router)
+
+		// expected errors (in order).
+		expected []error
+
+		// expected paths of routes with problems (in order).
+		paths []string
+	}
+
+	var cases = []caser{
+		// no problems.
+		{num: 1, setup: func(root *Router) {
+			root.Route("/users", handler).Methods(http.MethodGet)
+			root.Route("/users", handler).Methods(http.MethodPost)
+			root.Route("/users/me", handler)
+			root.Route("/users/{id}", handler)
+			root.Route("/users/{id}/{path...}", handler)
+		}},
+		// duplicate.
+		{num: 2, setup: func(root *Router) {
+			root.Route("/users/{id}", handler).Methods(http.MethodGet)
+			root.Route("/users/{id}", handler).Methods(http.MethodGet)
+		}, expected: []error{ErrDuplicateRoute}, paths: []string{"/users/{id}"}},
+		// shadowed by variable.
+		{num: 3, setup: func(root *Router) {
+			root.Route("/users/{id}", handler)
+			root.Route("/users/{name}", handler).Methods(http.MethodGet)
+			root.Route("/users/{num:int}", handler)
+		}, expected: []error{ErrDuplicateRoute, ErrShadowedRoute}, paths: []string{"/users/{name}", "/users/{num:int}"}},
+		// shadowed by catch-all.
+		{num: 4, setup: func(root *Router) {
+			root.Route("/files/*", handler)
+			root.Route("/files/{path...}", handler)
+		}, expected: []error{ErrDuplicateRoute}, paths: []string{"/files/{path...}"}},
+		// not shadowed: other methods, matchers, static beats variable.
+		{num: 5, setup: func(root *Router) {
+			root.Route("/users/{id}", handler).Methods(http.MethodGet)
+			root.Route("/users/{name}", handler).Methods(http.MethodPost)
+			root.Route("/users/{name}", handler).Headers("X-Name", "1")
+			root.Route("/users/{key}", handler).Queries("key", "1")
+			root.Route("/users/me", handler)
+		}},
+		// shadowed by group.
+		{num: 6, setup: func(root *Router) {
+			root.Group("/api").Route("/users", handler)
+			root.Route("/api/users", handler)
+		}, expected: []error{ErrShadowedRoute}, paths: []string{"/api/users"}},
+		// shadowed in group.
+		{num: 7, setup: func(root *Router) {
+			var api = root.Group("/api")
+			api.Route("/users/{id:int}", handler)
+			api.Route("/users/{num:int}", handler)
+			api.Route("/users/12", handler)
+		}, expected: []error{ErrDuplicateRoute}, paths: []string{"/api/users/{num:int}"}},
+		// ambiguous.
+		{num: 8, setup: func(root *Router) {
+			root.Route("/posts/{id:int}/edit", handler)
+			root.Route("/posts/{slug:slug}/edit", handler)
+		}, expected: []error{ErrAmbiguousRoute}, paths: []string{"/posts/{slug:slug}/edit"}},
+		// not ambiguous: disjoint types, other rest of path.
+		{num: 9, setup: func(root *Router) {
+			root.Route("/posts/{id:int}", handler)
+			root.Route("/posts/{name:alpha}", handler)
+			root.Route("/posts/{slug:slug}/edit", handler)
+		}},
+	}
+
+	for _, c := range cases {
+		var root = New()
+		c.setup(root)
+		var errs = root.Validate()
+		if len(errs) != len(c.expected) {
+			t.Fatalf("case %v: expected errors: %v | got: %v", c.num, c.expected, errs)
+		}
+		for i, err := range errs {
+			if !errors.Is(err, c.expected[i]) {
+				t.Fatalf("case %v: expected: %v | got: %v", c.num, c.expected[i], err)
+			}
+			var conflict *RouteConflict
+			if !errors.As(err, &conflict) || conflict.Route.Path != c.paths[i] {
+				t.Fatalf("case %v: expected path: %v | got: %v", c.num, c.paths[i], err)
+			}
+		}
+	}
+}
+
+func TestRouter_ValidateOrder(t *testing.T) {
+	var handler = func(w http.ResponseWriter, r *http.Request) {}
+	var root = New()
+	var expected []string
+	for _, resource := range []string{"posts", "users", "tags", "files", "books", "notes"} {
+		root.Route("/"+resource+"/{id:int}/edit", handler)
+		root.Route("/"+resource+"/{slug:slug}/edit", handler)
+	}
+	for _, resource := range []string{"books", "files", "notes", "posts", "tags", "users"} {
+		expected = append(expected, "/"+resource+"/{slug:slug}/edit")
+	}
+	for i := 0; i < 20; i++ {
+		var errs = root.Validate()
+		if len(errs) != len(expected) {
+			t.Fatalf("expected %v errors | got: %v", len(expected), errs)
+		}
+		for j, err := range errs {
+			var conflict *RouteConflict
+			if !errors.As(err, &conflict) || conflict.Route.Path != expected[j] {
+				t.Fatalf("run %v: expected path: %v | got: %v", i, expected[j], err)
+			}
+		}
+	}
+}
+
+func TestRouter_MustValidate(t *testing.T) {
+	var handler = func(w http.ResponseWriter, r *http.Request) {}
+	var root = New()
+	root.Route("/users", handler)
+	root.MustValidate()
+
+	root.Route("/users", handler)
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic")
+		}
+	}()
+	root.MustValidate()
+}
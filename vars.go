@@ -0,0 +1,154 @@
+package goway
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// route variable not exists.
+	ErrVarNotFound = errors.New("goway: route variable not found")
+
+	// route variable can't be converted to type.
+	ErrVarInvalid = errors.New("goway: invalid route variable")
+)
+
+// route variable conversion error.
+//
+// Return it from RouteE endpoint: error handler (see Router.ErrorHandler)
+// sends 400 by default.
+type VarError struct {
+	// variable name.
+	Name string
+
+	// variable value.
+	Value string
+
+	// expected type like: int, uuid, time.
+	Type string
+
+	// ErrVarNotFound or ErrVarInvalid.
+	Err error
+
+	// conversion error (if exists).
+	Cause error
+}
+
+func (v *VarError) Error() string {
+	if v.Err == ErrVarNotFound {
+		return fmt.Sprintf("%v: %v", v.Err, v.Name)
+	}
+	return fmt.Sprintf("%v: %v (%v): %q", v.Err, v.Name, v.Type, v.Value)
+}
+
+func (v *VarError) Unwrap() error {
+	return v.Err
+}
+
+// get route variable value.
+func getVar(request *http.Request, name string, varType string) (string, error) {
+	var found = getParams(request)
+	if found == nil {
+		return "", &VarError{Name: name, Type: varType, Err: ErrVarNotFound}
+	}
+	var value, ok = found.get(name)
+	if !ok {
+		return "", &VarError{Name: name, Type: varType, Err: ErrVarNotFound}
+	}
+	return value, nil
+}
+
+// create conversion error.
+func newVarError(name string, value string, varType string, cause error) error {
+	return &VarError{Name: name, Value: value, Type: varType, Err: ErrVarInvalid, Cause: cause}
+}
+
+// get route variable as int.
+func VarInt(request *http.Request, name string) (int, error) {
+	var value, err = getVar(request, name, "int")
+	if err != nil {
+		return 0, err
+	}
+	converted, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, newVarError(name, value, "int", err)
+	}
+	return converted, nil
+}
+
+// get route variable as int64.
+func VarInt64(request *http.Request, name string) (int64, error) {
+	var value, err = getVar(request, name, "int64")
+	if err != nil {
+		return 0, err
+	}
+	converted, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, newVarError(name, value, "int64", err)
+	}
+	return converted, nil
+}
+
+// get route variable as uint.
+func VarUint(request *http.Request, name string) (uint, error) {
+	var value, err = getVar(request, name, "uint")
+	if err != nil {
+		return 0, err
+	}
+	converted, err := strconv.ParseUint(value, 10, strconv.IntSize)
+	if err != nil {
+		return 0, newVarError(name, value, "uint", err)
+	}
+	return uint(converted), nil
+}
+
+// get route variable as bool (like strconv.ParseBool: 1, t, true, 0, f, false, etc).
+func VarBool(request *http.Request, name string) (bool, error) {
+	var value, err = getVar(request, name, "bool")
+	if err != nil {
+		return false, err
+	}
+	converted, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, newVarError(name, value, "bool", err)
+	}
+	return converted, nil
+}
+
+// get route variable as UUID bytes (like 123e4567-e89b-12d3-a456-426614174000).
+//
+// Result can be converted to types like uuid.UUID.
+func VarUUID(request *http.Request, name string) (uuid [16]byte, err error) {
+	value, err := getVar(request, name, "uuid")
+	if err != nil {
+		return
+	}
+	var hexed = strings.Replace(value, "-", "", 4)
+	var isValid = len(value) == 36 && len(hexed) == 32 &&
+		value[8] == '-' && value[13] == '-' && value[18] == '-' && value[23] == '-'
+	if !isValid {
+		return uuid, newVarError(name, value, "uuid", nil)
+	}
+	if _, err := hex.Decode(uuid[:], []byte(hexed)); err != nil {
+		return uuid, newVarError(name, value, "uuid", err)
+	}
+	return uuid, nil
+}
+
+// get route variable as time in layout (like time.RFC3339 or 2006-01-02).
+func VarTime(request *http.Request, name string, layout string) (time.Time, error) {
+	var value, err = getVar(request, name, "time")
+	if err != nil {
+		return time.Time{}, err
+	}
+	converted, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, newVarError(name, value, "time", err)
+	}
+	return converted, nil
+}
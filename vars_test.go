@@ -0,0 +1,191 @@
+package goway
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// get request with route variables.
+func requestWithVars(vars ...string) *http.Request {
+	var request = httptest.NewRequest(http.MethodGet, "/", nil)
+	for i := 0; i+1 < len(vars); i += 2 {
+		addVarToContext(request, vars[i], vars[i+1])
+	}
+	return request
+}
+
+func TestVarAccessors(t *testing.T) {
+	var request = requestWithVars(
+		"int", "-12",
+		"uint", "12",
+		"bool", "true",
+		"uuid", "123e4567-e89b-12d3-a456-426614174000",
+		"time", "2022-05-01",
+		"bad", "12a",
+	)
+
+	if value, err := VarInt(request, "int"); err != nil || value != -12 {
+		t.Fatalf("VarInt: %v, %v", value, err)
+	}
+	if value, err := VarInt64(request, "int"); err != nil || value != -12 {
+		t.Fatalf("VarInt64: %v, %v", value, err)
+	}
+	if value, err := VarUint(request, "uint"); err != nil || value != 12 {
+		t.Fatalf("VarUint: %v, %v", value, err)
+	}
+	if value, err := VarBool(request, "bool"); err != nil || !value {
+		t.Fatalf("VarBool: %v, %v", value, err)
+	}
+	var expectedUUID = [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+	if value, err := VarUUID(request, "uuid"); err != nil || value != expectedUUID {
+		t.Fatalf("VarUUID: %v, %v", value, err)
+	}
+	var expectedTime = time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+	if value, err := VarTime(request, "time", "2006-01-02"); err != nil || !value.Equal(expectedTime) {
+		t.Fatalf("VarTime: %v, %v", value, err)
+	}
+
+	// errors.
+	type caser struct {
+		num      int
+		err      error
+		expected error
+	}
+	var errOf = func(_ interface{}, err error) error {
+		return err
+	}
+	var cases = []caser{
+		{num: 1, err: errOf(VarInt(request, "bad")), expected: ErrVarInvalid},
+		{num: 2, err: errOf(VarInt64(request, "bad")), expected: ErrVarInvalid},
+		{num: 3, err: errOf(VarUint(request, "int")), expected: ErrVarInvalid},
+		{num: 4, err: errOf(VarBool(request, "bad")), expected: ErrVarInvalid},
+		{num: 5, err: errOf(VarUUID(request, "bad")), expected: ErrVarInvalid},
+		{num: 6, err: errOf(VarUUID(request, "time")), expected: ErrVarInvalid},
+		{num: 7, err: errOf(VarTime(request, "bad", "2006-01-02")), expected: ErrVarInvalid},
+		{num: 8, err: errOf(VarInt(request, "notExists")), expected: ErrVarNotFound},
+	}
+	for _, c := range cases {
+		if !errors.Is(c.err, c.expected) {
+			t.Fatalf("case num: %v | expected: %v | got: %v", c.num, c.expected, c.err)
+		}
+		var varErr *VarError
+		if !errors.As(c.err, &varErr) {
+			t.Fatalf("case num: %v | expected VarError", c.num)
+		}
+	}
+}
+
+func TestRouter_VarErrors(t *testing.T) {
+	var root = New()
+	root.RouteE("/users/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		var id, err = VarInt(r, "id")
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(w, id)
+		return nil
+	})
+	var api = root.Group("/api").ErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		var varErr *VarError
+		if errors.As(err, &varErr) {
+			w.WriteHeader(400)
+			fmt.Fprint(w, "api: ", varErr.Name)
+		}
+	})
+	api.RouteE("/posts/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		var _, err = VarUUID(r, "id")
+		return err
+	})
+	// variable error in middleware.
+	root.Group("/mw/{id}").Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, err := VarInt(r, "id"); err != nil {
+				HandlerError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}).Route("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	})
+
+	type caser struct {
+		num      int
+		path     string
+		status   int
+		expected string
+	}
+	var cases = []caser{
+		{num: 1, path: "/users/12", status: 200, expected: "12"},
+		{num: 2, path: "/users/abc", status: 400, expected: "goway: invalid route variable: id (int): \"abc\"\n"},
+		{num: 3, path: "/api/posts/abc", status: 400, expected: "api: id"},
+		{num: 4, path: "/mw/12", status: 200, expected: "ok"},
+		{num: 5, path: "/mw/abc", status: 400, expected: "goway: invalid route variable: id (int): \"abc\"\n"},
+	}
+	for _, c := range cases {
+		var recorder = httptest.NewRecorder()
+		root.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, c.path, nil))
+		if recorder.Code != c.status || recorder.Body.String() != c.expected {
+			t.Fatalf("case num: %v | expected: %v %q | got: %v %q", c.num, c.status, c.expected, recorder.Code, recorder.Body.String())
+		}
+	}
+}
+
+func TestVarInt_Allocs(t *testing.T) {
+	var request = httptest.NewRequest(http.MethodGet, "/", nil)
+	addVarToContext(request, "id", "12")
+	var allocs = testing.AllocsPerRun(100, func() {
+		VarInt(request, "id")
+	})
+	if allocs > 0 {
+		t.Fatalf("expected 0 allocations | got: %v", allocs)
+	}
+}
+
+func TestRouting_NestedGroupVars(t *testing.T) {
+	var root = New()
+	var middlewareVars map[string]string
+	var api = root.Group("/api/{version}")
+	var users = api.Group("/users/{id}").Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			middlewareVars = Vars(r)
+			next.ServeHTTP(w, r)
+		})
+	})
+	users.Route("/posts/{post}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, Param(r, "version"), Param(r, "id"), Param(r, "post"))
+	})
+	users.Route("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "user ", Param(r, "id"))
+	})
+
+	type caser struct {
+		num      int
+		router   *Router
+		path     string
+		status   int
+		expected string
+	}
+	var cases = []caser{
+		{num: 1, router: root, path: "/api/v1/users/12/posts/3", status: 200, expected: "v1123"},
+		{num: 2, router: root, path: "/api/v1/users/12", status: 200, expected: "user 12"},
+		{num: 3, router: root, path: "/api/v1/users/12/posts", status: 404, expected: "not found"},
+		// group served without parent: matches path after its prefix.
+		{num: 4, router: api, path: "/api/v1/users/12/posts/3", status: 200, expected: "123"},
+	}
+	for _, c := range cases {
+		middlewareVars = nil
+		var recorder = httptest.NewRecorder()
+		c.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, c.path, nil))
+		if recorder.Code != c.status || recorder.Body.String() != c.expected {
+			t.Fatalf("case num: %v | expected: %v %q | got: %v %q", c.num, c.status, c.expected, recorder.Code, recorder.Body.String())
+		}
+		if c.status == 200 && middlewareVars["id"] != "12" {
+			t.Fatalf("case num: %v | expected vars in group middleware | got: %v", c.num, middlewareVars)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package goway
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// route information.
+type RouteInfo struct {
+	// full path template like: /api/users/{id}.
+	Path string `json:"path"`
+
+	// allowed methods (route and its groups). Empty - any method.
+	Methods []string `json:"methods"`
+
+	// route name (if set).
+	Name string `json:"name,omitempty"`
+
+	// host pattern (if set).
+	Host string `json:"host,omitempty"`
+
+	// is route mounted handler (serves all paths under Path)?
+	Mount bool `json:"mount,omitempty"`
+
+	// how much middlewares applied to route (route, its groups and root).
+	Middleware int `json:"middleware"`
+
+	// how much groups above route (0 - route of root router).
+	Depth int `json:"depth"`
+}
+
+// calls walkFn for every route of router and its groups.
+//
+// Routes of router visited first, then groups (in registration order).
+// If walkFn returns error, walking stops and error returned.
+func (r *Router) Walk(walkFn func(info RouteInfo) error) error {
+	for _, route := range r.routes {
+		if err := walkFn(route.info()); err != nil {
+			return err
+		}
+	}
+	for _, group := range r.groups {
+		if err := group.Walk(walkFn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// get route information.
+func (r *Route) info() RouteInfo {
+	var info = RouteInfo{
+		Path:       "/" + strings.Join(r.fullPathSlice(), "/"),
+		Name:       r.name,
+		Mount:      r.isMount,
+		Middleware: r.middlewareCount,
+	}
+	if host := r.getHostPattern(); host != nil {
+		info.Host = strings.Join(host.labels, ".")
+	}
+	for router := r.router; router != nil; router = router.parent {
+		info.Middleware += router.middlewareCount
+		if router.parent != nil {
+			info.Depth++
+		}
+	}
+	// copy (and empty slice instead of nil).
+	info.Methods = append(make([]string, 0), r.effectiveMethods()...)
+	return info
+}
+
+// get methods allowed by route and its groups. nil - any method.
+func (r *Route) effectiveMethods() []string {
+	return intersectMethods(r.allowedMethods, r.router.effectiveMethods())
+}
+
+// get methods allowed by router and its parents. nil - any method.
+func (r *Router) effectiveMethods() (methods []string) {
+	for router := r; router != nil; router = router.parent {
+		methods = intersectMethods(methods, router.allowedMethods)
+	}
+	return
+}
+
+// get methods allowed by both. nil - any method.
+func intersectMethods(first []string, second []string) []string {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	var result = make([]string, 0)
+	for _, method := range first {
+		if isMethodAllowed(second, method) {
+			result = append(result, method)
+		}
+	}
+	return result
+}
+
+// table of routes.
+type RouteTable []RouteInfo
+
+// get table of all routes sorted by path and methods.
+func (r *Router) Routes() RouteTable {
+	var table = make(RouteTable, 0)
+	r.Walk(func(info RouteInfo) error {
+		table = append(table, info)
+		return nil
+	})
+	sort.SliceStable(table, func(i, j int) bool {
+		if table[i].Path != table[j].Path {
+			return table[i].Path < table[j].Path
+		}
+		return strings.Join(table[i].Methods, ",") < strings.Join(table[j].Methods, ",")
+	})
+	return table
+}
+
+// render table as text like:
+//
+//	METHODS    PATH              NAME   HOST  MIDDLEWARE  DEPTH
+//	GET        /api/users/{id}   user   -     1           1
+func (t RouteTable) String() string {
+	var builder strings.Builder
+	var writer = tabwriter.NewWriter(&builder, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "METHODS\tPATH\tNAME\tHOST\tMIDDLEWARE\tDEPTH")
+	for _, info := range t {
+		var methods = strings.Join(info.Methods, ",")
+		if len(methods) < 1 {
+			methods = "ANY"
+		}
+		var routePath = info.Path
+		if info.Mount {
+			routePath = strings.TrimSuffix(routePath, "/") + "/*"
+		}
+		fmt.Fprintf(writer, "%v\t%v\t%v\t%v\t%v\t%v\n",
+			methods, routePath, orDash(info.Name), orDash(info.Host), info.Middleware, info.Depth)
+	}
+	writer.Flush()
+	return builder.String()
+}
+
+// render table as JSON.
+func (t RouteTable) JSON() ([]byte, error) {
+	return json.MarshalIndent(t, "", "  ")
+}
+
+// get "-" if str empty.
+func orDash(str string) string {
+	if len(str) < 1 {
+		return "-"
+	}
+	return str
+}
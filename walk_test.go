@@ -0,0 +1,74 @@
+package goway
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRouter_Walk(t *testing.T) {
+	var middleware = func(next http.Handler) http.Handler {
+		return next
+	}
+	var handler = func(w http.ResponseWriter, r *http.Request) {}
+	var root = New().Use(middleware)
+	root.Route("/", handler).Name("index")
+	var api = root.Group("/api").Methods(http.MethodGet, http.MethodPost).Use(middleware, nil)
+	api.Route("/users/{id}", handler).Methods(http.MethodGet, http.MethodDelete).Name("user").Use(middleware)
+	api.Group("").Host("{tenant}.example.com").Mount("/files", http.NotFoundHandler())
+
+	var expected = RouteTable{
+		{Path: "/", Methods: []string{}, Name: "index", Middleware: 1, Depth: 0},
+		{Path: "/api/users/{id}", Methods: []string{http.MethodGet}, Name: "user", Middleware: 3, Depth: 1},
+		{Path: "/api/files", Methods: []string{http.MethodGet, http.MethodPost}, Host: "{tenant}.example.com", Mount: true, Middleware: 2, Depth: 2},
+	}
+	var walked = make(RouteTable, 0)
+	var err = root.Walk(func(info RouteInfo) error {
+		walked = append(walked, info)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(walked, expected) {
+		t.Fatalf("expected: %v | got: %v", expected, walked)
+	}
+
+	// stop walking.
+	var errStop = errors.New("stop")
+	var count = 0
+	err = root.Walk(func(info RouteInfo) error {
+		count++
+		return errStop
+	})
+	if !errors.Is(err, errStop) || count != 1 {
+		t.Fatalf("walk should stop on error")
+	}
+
+	// sorted table.
+	var table = root.Routes()
+	var sortedPaths = []string{"/", "/api/files", "/api/users/{id}"}
+	for i, info := range table {
+		if info.Path != sortedPaths[i] {
+			t.Fatalf("expected path: %v | got: %v", sortedPaths[i], info.Path)
+		}
+	}
+	var text = table.String()
+	if !strings.Contains(text, "GET,POST") || !strings.Contains(text, "/api/files/*") || !strings.Contains(text, "ANY") {
+		t.Fatalf("wrong text table: %v", text)
+	}
+	var rendered, jsonErr = table.JSON()
+	if jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+	var decoded RouteTable
+	if err = json.Unmarshal(rendered, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, table) {
+		t.Fatalf("JSON table not same")
+	}
+}